@@ -4,10 +4,20 @@ package esc_sdk
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"gopkg.in/ghodss/yaml.v1"
+	yamlv2 "gopkg.in/yaml.v2"
 )
 
 // EscClient is a client for the ESC API.
@@ -20,11 +30,18 @@ type EscClient struct {
 // NewAuthContext creates a new context with the given access token.
 // This context can be used to authenticate requests to the ESC API.
 func NewAuthContext(accessToken string) context.Context {
+	return NewAuthContextWithPrefix(accessToken, "token")
+}
+
+// NewAuthContextWithPrefix creates a new context with the given access token and Authorization scheme prefix,
+// e.g. "Bearer" for proxies that expect bearer tokens. If prefix is empty, the token is sent as the entire
+// Authorization header value.
+func NewAuthContextWithPrefix(accessToken, prefix string) context.Context {
 	return context.WithValue(
 		context.Background(),
 		ContextAPIKeys,
 		map[string]APIKey{
-			"Authorization": {Key: accessToken, Prefix: "token"},
+			"Authorization": {Key: accessToken, Prefix: prefix},
 		},
 	)
 }
@@ -36,6 +53,33 @@ func NewClient(cfg *Configuration) *EscClient {
 	return client
 }
 
+// Option customizes the configuration built by NewClientWithOptions.
+type Option func(*Configuration)
+
+// WithDefaultHeader adds a header that is sent with each request.
+func WithDefaultHeader(name, value string) Option {
+	return func(cfg *Configuration) { cfg.AddDefaultHeader(name, value) }
+}
+
+// WithTokenProvider sets the configuration's TokenProvider.
+func WithTokenProvider(provider func(ctx context.Context) (string, error)) Option {
+	return func(cfg *Configuration) { cfg.TokenProvider = provider }
+}
+
+// NewClientWithOptions creates a new ESC client that sends requests to the given base URL, e.g.
+// `https://api.pulumi.com/api/esc` or the URL of an httptest.Server, using the given HTTP client. If httpClient is
+// nil, http.DefaultClient is used. The options are applied in order to a configuration created by NewConfiguration,
+// after the server and HTTP client have been set.
+func NewClientWithOptions(baseURL string, httpClient *http.Client, opts ...Option) *EscClient {
+	cfg := NewConfiguration()
+	cfg.Servers = ServerConfigurations{{URL: strings.TrimSuffix(baseURL, "/")}}
+	cfg.HTTPClient = httpClient
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return NewClient(cfg)
+}
+
 // ListEnvironments lists all environments in the given organization.
 // If a continuation token is provided, the list will start from that token.
 func (c *EscClient) ListEnvironments(ctx context.Context, org string, continuationToken *string) (*OrgEnvironments, error) {
@@ -48,12 +92,140 @@ func (c *EscClient) ListEnvironments(ctx context.Context, org string, continuati
 	return envs, err
 }
 
+// ListAllEnvironments lists all environments in the given organization, following continuation tokens until every page has been read.
+func (c *EscClient) ListAllEnvironments(ctx context.Context, org string) ([]OrgEnvironment, error) {
+	var all []OrgEnvironment
+	var continuationToken *string
+	for {
+		envs, err := c.ListEnvironments(ctx, org, continuationToken)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, envs.Environments...)
+
+		continuationToken = envs.NextToken
+		if len(envs.Environments) == 0 || continuationToken == nil || *continuationToken == "" {
+			return all, nil
+		}
+	}
+}
+
+// ListEnvironmentsModifiedSince lists the environments in the given organization that were modified after the given time,
+// in list order.
+//
+// The list endpoint has no server-side filter, so every page of the organization's environments is read and filtered on
+// the modification time it reports; this costs one request per page. Only environments whose modification time is
+// missing or unrecognized fall back to reading their latest revision, which costs one request per such environment.
+func (c *EscClient) ListEnvironmentsModifiedSince(ctx context.Context, org string, since time.Time) ([]OrgEnvironment, error) {
+	envs, err := c.ListAllEnvironments(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	modified := make([]bool, len(envs))
+	var unknown []int
+	for i, env := range envs {
+		t, err := parseRevisionTime(env.Modified)
+		if err != nil {
+			unknown = append(unknown, i)
+			continue
+		}
+		modified[i] = t.After(since)
+	}
+
+	err = runBounded(ctx, unknown, maxConcurrentReads, func(ctx context.Context, i int) error {
+		revs, _, err := c.EscAPI.ListEnvironmentRevisions(ctx, org, envs[i].Name).Count(1).Execute()
+		if err != nil {
+			return err
+		}
+		for _, rev := range revs {
+			created, err := parseRevisionTime(rev.GetCreated())
+			if err != nil {
+				return fmt.Errorf("environment %s: revision %d: %w", envs[i].Name, rev.Number, err)
+			}
+			if created.After(since) {
+				modified[i] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []OrgEnvironment
+	for i, env := range envs {
+		if modified[i] {
+			result = append(result, env)
+		}
+	}
+	return result, nil
+}
+
+// maxConcurrentDeletes bounds the number of environments DeleteEnvironmentsMatching deletes at once.
+const maxConcurrentDeletes = 8
+
+// DeleteEnvironmentsMatching deletes every environment in the given organization for which match returns true.
+// Before anything is deleted, confirm is called with the full list of matching environments; nothing is deleted
+// unless it returns true. The names of the deleted environments are returned along with the first error encountered;
+// no further deletions are started once an error has occurred.
+func (c *EscClient) DeleteEnvironmentsMatching(
+	ctx context.Context,
+	org string,
+	match func(OrgEnvironment) bool,
+	confirm func(toDelete []OrgEnvironment) bool,
+) ([]string, error) {
+	if match == nil {
+		return nil, errors.New("a match callback is required")
+	}
+	if confirm == nil {
+		return nil, errors.New("a confirm callback is required")
+	}
+
+	envs, err := c.ListAllEnvironments(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	var toDelete []OrgEnvironment
+	for _, env := range envs {
+		if match(env) {
+			toDelete = append(toDelete, env)
+		}
+	}
+	if len(toDelete) == 0 || !confirm(toDelete) {
+		return nil, nil
+	}
+
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+	err = runBounded(ctx, toDelete, maxConcurrentDeletes, func(ctx context.Context, env OrgEnvironment) error {
+		if err := c.DeleteEnvironment(ctx, org, env.Name); err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		deleted = append(deleted, env.Name)
+		return nil
+	})
+
+	return deleted, err
+}
+
 // GetEnvironment retrieves the environment with the given name in the given organization.
-// The environment is returned along with the raw YAML definition.
+// The environment is returned along with the raw YAML definition exactly as stored, including comments and formatting.
 func (c *EscClient) GetEnvironment(ctx context.Context, org, envName string) (*EnvironmentDefinition, string, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, "", err
+	}
+
 	env, resp, err := c.EscAPI.GetEnvironment(ctx, org, envName).Execute()
 	if err != nil {
-		return nil, "", err
+		return nil, "", wrapNotFound(err, resp, org, envName)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -64,12 +236,74 @@ func (c *EscClient) GetEnvironment(ctx context.Context, org, envName string) (*E
 	return env, string(body), nil
 }
 
+// environmentReader reads the definition of an environment as GetEnvironment does. Methods that are built on
+// GetEnvironment take one, so that CachingClient can serve them from its cache.
+type environmentReader func(ctx context.Context, org, envName string) (*EnvironmentDefinition, string, error)
+
+// GetEnvironmentRaw retrieves the YAML definition of the environment with the given name in the given organization
+// byte for byte as stored, including comments and formatting, without decoding it. Use it where formatting must be
+// preserved, e.g. in editors; definitions re-marshaled from an EnvironmentDefinition, such as those produced by
+// MarshalEnvironmentDefinition and GetEnvironmentYamlCanonical, lose comments and formatting.
+func (c *EscClient) GetEnvironmentRaw(ctx context.Context, org, envName string) (string, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return "", err
+	}
+
+	path, err := c.environmentURL(ctx, "GetEnvironment", org, envName)
+	if err != nil {
+		return "", err
+	}
+
+	resp, body, err := c.rawRequest(ctx, "GetEnvironment", http.MethodGet, path, map[string]string{"Accept": "application/x-yaml"}, nil)
+	if err != nil {
+		return "", wrapNotFound(err, resp, org, envName)
+	}
+	return string(body), nil
+}
+
+// GetEnvironmentJSON retrieves the environment with the given name in the given organization as JSON.
+// The environment is returned along with the raw JSON definition. If the service does not honor the request
+// for JSON, the YAML it returns is converted so that the raw definition is always JSON.
+func (c *EscClient) GetEnvironmentJSON(ctx context.Context, org, envName string) (*EnvironmentDefinition, []byte, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, nil, err
+	}
+
+	path, err := c.environmentURL(ctx, "GetEnvironment", org, envName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, body, err := c.rawRequest(ctx, "GetEnvironment", http.MethodGet, path, map[string]string{"Accept": "application/json"}, nil)
+	if err != nil {
+		return nil, nil, wrapNotFound(err, resp, org, envName)
+	}
+
+	if !JsonCheck.MatchString(resp.Header.Get("Content-Type")) {
+		body, err = yaml.YAMLToJSON(body)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var env EnvironmentDefinition
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, nil, err
+	}
+
+	return &env, body, nil
+}
+
 // GetEnvironmentAtVersion retrieves the environment with the given name in the given organization at the given version.
 // The environment is returned along with the raw YAML definition.
 func (c *EscClient) GetEnvironmentAtVersion(ctx context.Context, org, envName, version string) (*EnvironmentDefinition, string, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, "", err
+	}
+
 	env, resp, err := c.EscAPI.GetEnvironmentAtVersion(ctx, org, envName, version).Execute()
 	if err != nil {
-		return nil, "", err
+		return nil, "", wrapNotFound(err, resp, org, envName)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -80,22 +314,88 @@ func (c *EscClient) GetEnvironmentAtVersion(ctx context.Context, org, envName, v
 	return env, string(body), nil
 }
 
+// GetEnvironmentETag returns the ETag identifying the current revision of the environment with the given name
+// in the given organization, without reading its definition.
+func (c *EscClient) GetEnvironmentETag(ctx context.Context, org, envName string) (string, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return "", err
+	}
+
+	resp, err := c.EscAPI.GetEnvironmentETag(ctx, org, envName).Execute()
+	if err != nil {
+		return "", wrapNotFound(err, resp, org, envName)
+	}
+
+	return resp.Header.Get("ETag"), nil
+}
+
+// GetEnvironmentYamlCanonical retrieves the definition of the environment with the given name in the given organization
+// and returns it as canonical YAML, as produced by MarshalEnvironmentDefinitionOrdered. Round-tripping an environment
+// through this method yields stable output that is suitable for committing to source control.
+func (c *EscClient) GetEnvironmentYamlCanonical(ctx context.Context, org, envName string) (string, error) {
+	return environmentYamlCanonical(ctx, org, envName, c.GetEnvironment)
+}
+
+// environmentYamlCanonical implements GetEnvironmentYamlCanonical, reading the definition with getEnvironment.
+func environmentYamlCanonical(ctx context.Context, org, envName string, getEnvironment environmentReader) (string, error) {
+	env, _, err := getEnvironment(ctx, org, envName)
+	if err != nil {
+		return "", err
+	}
+
+	return MarshalEnvironmentDefinitionOrdered(env)
+}
+
 // OpenEnvironment opens the environment with the given name in the given organization.
 // The open environment is returned, which contains the ID of the opened environment session to use with ReadOpenEnvironment.
 func (c *EscClient) OpenEnvironment(ctx context.Context, org, envName string) (*OpenEnvironment, error) {
-	openInfo, _, err := c.EscAPI.OpenEnvironment(ctx, org, envName).Execute()
-	return openInfo, err
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
+	openInfo, resp, err := c.EscAPI.OpenEnvironment(ctx, org, envName).Execute()
+	return openInfo, wrapNotFound(err, resp, org, envName)
+}
+
+// OpenEnvironmentWithDiagnostics is like OpenEnvironment, but also returns the diagnostics reported by the service.
+// Diagnostics may accompany a successful open, e.g. warnings about degraded resolution. If the open fails because
+// the environment is invalid, the diagnostics that explain why are returned along with the error.
+func (c *EscClient) OpenEnvironmentWithDiagnostics(ctx context.Context, org, envName string) (*OpenEnvironment, []EnvironmentDiagnostic, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, nil, err
+	}
+
+	openInfo, resp, err := c.EscAPI.OpenEnvironment(ctx, org, envName).Execute()
+	if err != nil {
+		var apiErr *GenericOpenAPIError
+		if errors.As(err, &apiErr) {
+			if diags, ok := apiErr.Model().(EnvironmentDiagnostics); ok {
+				return nil, diags.Diagnostics, err
+			}
+		}
+		return nil, nil, wrapNotFound(err, resp, org, envName)
+	}
+
+	return openInfo, openInfo.GetDiagnostics().Diagnostics, nil
 }
 
 // OpenEnvironmentAtVersion opens the environment with the given name in the given organization at the given version.
 // The open environment is returned, which contains the ID of the opened environment session to use with ReadOpenEnvironment.
 func (c *EscClient) OpenEnvironmentAtVersion(ctx context.Context, org, envName, version string) (*OpenEnvironment, error) {
-	openInfo, _, err := c.EscAPI.OpenEnvironmentAtVersion(ctx, org, envName, version).Execute()
-	return openInfo, err
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
+	openInfo, resp, err := c.EscAPI.OpenEnvironmentAtVersion(ctx, org, envName, version).Execute()
+	return openInfo, wrapNotFound(err, resp, org, envName)
 }
 
 // ReadOpenEnvironment reads the environment with the given open session ID and returns the config and resolved secret values.
 func (c *EscClient) ReadOpenEnvironment(ctx context.Context, org, envName, openEnvID string) (*Environment, map[string]any, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, nil, err
+	}
+
 	env, _, err := c.EscAPI.ReadOpenEnvironment(ctx, org, envName, openEnvID).Execute()
 	if err != nil {
 		return nil, nil, err
@@ -138,29 +438,168 @@ func (c *EscClient) OpenAndReadEnvironmentAtVersion(ctx context.Context, org, en
 	return c.ReadOpenEnvironment(ctx, org, envName, openInfo.Id)
 }
 
+// ErrNoRevisionAtTime is returned when an environment is read at a time before its first revision was created.
+var ErrNoRevisionAtTime = errors.New("environment has no revision at the given time")
+
+// EnvironmentRevisionAtTime returns the revision of the environment with the given name in the given organization
+// that was current at the given time, i.e. the most recent revision created at or before t.
+// If t precedes the environment's first revision, ErrNoRevisionAtTime is returned.
+func (c *EscClient) EnvironmentRevisionAtTime(ctx context.Context, org, envName string, t time.Time) (*EnvironmentRevision, error) {
+	revs, err := c.ListAllEnvironmentRevisions(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *EnvironmentRevision
+	var foundAt time.Time
+	for i := range revs {
+		rev := &revs[i]
+		created, err := parseRevisionTime(rev.GetCreated())
+		if err != nil {
+			return nil, fmt.Errorf("revision %d: %w", rev.Number, err)
+		}
+		if created.After(t) {
+			continue
+		}
+		if found == nil || created.After(foundAt) || (created.Equal(foundAt) && rev.Number > found.Number) {
+			found, foundAt = rev, created
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoRevisionAtTime, t.Format(time.RFC3339))
+	}
+	return found, nil
+}
+
+// OpenAndReadEnvironmentAtTime opens and reads the environment with the given name in the given organization
+// as it was defined at the given time. History is revision-based: the revision that was current at t is selected
+// with EnvironmentRevisionAtTime and read with OpenAndReadEnvironmentAtVersion. Imported environments and dynamic
+// providers are resolved as of now, not as of t.
+// If t precedes the environment's first revision, ErrNoRevisionAtTime is returned.
+func (c *EscClient) OpenAndReadEnvironmentAtTime(ctx context.Context, org, envName string, t time.Time) (*Environment, map[string]any, error) {
+	rev, err := c.EnvironmentRevisionAtTime(ctx, org, envName, t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.OpenAndReadEnvironmentAtVersion(ctx, org, envName, strconv.Itoa(int(rev.Number)))
+}
+
+// parseRevisionTime parses the creation timestamp of an environment revision.
+func parseRevisionTime(created string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999"} {
+		if t, err := time.Parse(layout, created); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized revision timestamp %q", created)
+}
+
 // ReadEnvironmentProperty reads the property at the given path in the environment with the given open session ID.
 // The property is returned along with the resolved value.
 func (c *EscClient) ReadEnvironmentProperty(ctx context.Context, org, envName, openEnvID, propPath string) (*Value, any, error) {
-	prop, _, err := c.EscAPI.ReadOpenEnvironmentProperty(ctx, org, envName, openEnvID).Property(propPath).Execute()
-	v := mapValuesPrimitive(prop.Value)
-	return prop, v, err
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, nil, err
+	}
+
+	prop, resp, err := c.EscAPI.ReadOpenEnvironmentProperty(ctx, org, envName, openEnvID).Property(propPath).Execute()
+	if err != nil {
+		return nil, nil, wrapNotFound(err, resp, org, envName)
+	}
+
+	return prop, resolvePropertyValue(prop), nil
+}
+
+// ResolveProperty opens the environment with the given name in the given organization and returns the resolved value
+// of the property at the given path, e.g. `environmentVariables.DB_PASSWORD`, as plain Go values.
+//
+// The service resolves the whole environment when it is opened, including every dynamic provider, and has no way
+// to open just one property. ResolveProperty therefore saves only the transfer of the other values, not the cost of
+// resolving them; to read several properties, open the environment once and call ReadEnvironmentProperty for each.
+func (c *EscClient) ResolveProperty(ctx context.Context, org, envName, propPath string) (any, error) {
+	openInfo, err := c.OpenEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	prop, resp, err := c.EscAPI.ReadOpenEnvironmentProperty(ctx, org, envName, openInfo.Id).Property(propPath).Execute()
+	if err != nil {
+		return nil, wrapNotFound(err, resp, org, envName)
+	}
+	return resolvePropertyValue(prop), nil
+}
+
+// ReadOpenEnvironmentFile reads the file at the given property path, e.g. `files.KUBECONFIG`, in the environment with
+// the given open session ID and returns its contents. The property must resolve to a string.
+//
+// Values are transmitted as JSON strings, which cannot carry bytes that are not valid UTF-8. Binary files such as
+// keystores should be stored base64-encoded, in which case the encoded text is returned for the caller to decode;
+// the contents are never decoded implicitly, since text files may happen to be valid base64.
+func (c *EscClient) ReadOpenEnvironmentFile(ctx context.Context, org, envName, openEnvID, propPath string) ([]byte, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
+	prop, resp, err := c.EscAPI.ReadOpenEnvironmentProperty(ctx, org, envName, openEnvID).Property(propPath).Execute()
+	if err != nil {
+		return nil, wrapNotFound(err, resp, org, envName)
+	}
+
+	value := resolvePropertyValue(prop)
+	contents, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("file %v must be a string, not %T", propPath, value)
+	}
+	return []byte(contents), nil
 }
 
 // CreateEnvironment creates a new environment with the given name in the given organization.
+// If the environment already exists, the error returned matches ErrEnvironmentExists; see CreateEnvironmentIfNotExists.
 func (c *EscClient) CreateEnvironment(ctx context.Context, org, envName string) error {
-	_, _, err := c.EscAPI.CreateEnvironment(ctx, org, envName).Execute()
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return err
+	}
+
+	_, resp, err := c.EscAPI.CreateEnvironment(ctx, org, envName).Execute()
+	if err != nil && resp != nil && resp.StatusCode == http.StatusConflict {
+		return fmt.Errorf("%w: %s/%s: %v", ErrEnvironmentExists, org, envName, err)
+	}
 	return err
 }
 
+// CreateEnvironmentIfNotExists creates a new environment with the given name in the given organization unless it
+// already exists. It reports whether the environment was created; an existing environment is not an error, unlike
+// with CreateEnvironment.
+func (c *EscClient) CreateEnvironmentIfNotExists(ctx context.Context, org, envName string) (bool, error) {
+	if err := c.CreateEnvironment(ctx, org, envName); err != nil {
+		if errors.Is(err, ErrEnvironmentExists) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 // UpdateEnvironmentYaml updates the environment with the given name in the given organization with the given YAML definition.
+// Empty or whitespace-only YAML is rejected with ErrEmptyEnvironmentYaml; send `values: {}` to empty an environment.
 func (c *EscClient) UpdateEnvironmentYaml(ctx context.Context, org, envName, yaml string) (*EnvironmentDiagnostics, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(yaml) == "" {
+		return nil, ErrEmptyEnvironmentYaml
+	}
+
 	diags, _, err := c.EscAPI.UpdateEnvironmentYaml(ctx, org, envName).Body(yaml).Execute()
 	return diags, err
 }
 
 // UpdateEnvironment updates the environment with the given name in the given organization with the given definition.
+// If the client's configuration enables DetectImportCycles, the update is rejected with an ImportCycleError
+// before it is sent if the definition would create an import cycle.
 func (c *EscClient) UpdateEnvironment(ctx context.Context, org, envName string, env *EnvironmentDefinition) (*EnvironmentDiagnostics, error) {
-	yaml, err := MarshalEnvironmentDefinition(env)
+	yaml, err := c.marshalEnvironmentUpdate(ctx, org, envName, env)
 	if err != nil {
 		return nil, err
 	}
@@ -169,10 +608,46 @@ func (c *EscClient) UpdateEnvironment(ctx context.Context, org, envName string,
 	return diags, err
 }
 
+// UpdateEnvironmentDryRun checks the given definition exactly as UpdateEnvironment would send it for the environment
+// with the given name in the given organization, without applying it. The definition is marshaled and pre-checked
+// as by UpdateEnvironment and then checked with CheckEnvironmentYaml.
+func (c *EscClient) UpdateEnvironmentDryRun(ctx context.Context, org, envName string, env *EnvironmentDefinition) (*CheckEnvironment, error) {
+	yaml, err := c.marshalEnvironmentUpdate(ctx, org, envName, env)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CheckEnvironmentYaml(ctx, org, yaml)
+}
+
+// marshalEnvironmentUpdate marshals a definition for UpdateEnvironment, first validating the names and checking for
+// import cycles if the client's configuration enables ValidateNames and DetectImportCycles, respectively.
+func (c *EscClient) marshalEnvironmentUpdate(ctx context.Context, org, envName string, env *EnvironmentDefinition) (string, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return "", err
+	}
+
+	if c.rawClient.cfg.DetectImportCycles {
+		cycle, err := c.DetectImportCycle(ctx, org, env, envName)
+		if err != nil {
+			return "", err
+		}
+		if cycle != nil {
+			return "", &ImportCycleError{Cycle: cycle}
+		}
+	}
+
+	return MarshalEnvironmentDefinition(env)
+}
+
 // DeleteEnvironment deletes the environment with the given name in the given organization.
 func (c *EscClient) DeleteEnvironment(ctx context.Context, org, envName string) error {
-	_, _, err := c.EscAPI.DeleteEnvironment(ctx, org, envName).Execute()
-	return err
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return err
+	}
+
+	_, resp, err := c.EscAPI.DeleteEnvironment(ctx, org, envName).Execute()
+	return wrapNotFound(err, resp, org, envName)
 }
 
 // CheckEnvironment checks the given environment definition for errors.
@@ -197,20 +672,87 @@ func (c *EscClient) CheckEnvironmentYaml(ctx context.Context, org, yaml string)
 	return check, err
 }
 
+// ValidateProviderInputs checks the given inputs to the dynamic provider with the given name, e.g. `aws-login`,
+// without opening an environment. The inputs are checked within a synthesized environment whose only value is
+// `values.provider` set to `fn::open::<provider>: inputs`, so diagnostics for bad inputs are reported at paths
+// beneath `provider["fn::open::<provider>"]`. As with CheckEnvironmentYaml, the check result is returned along with
+// any error.
+func (c *EscClient) ValidateProviderInputs(ctx context.Context, org, provider string, inputs map[string]any) (*CheckEnvironment, error) {
+	if inputs == nil {
+		inputs = map[string]any{}
+	}
+	def := map[string]any{
+		"values": map[string]any{
+			"provider": map[string]any{"fn::open::" + provider: inputs},
+		},
+	}
+
+	bs, err := yaml.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+	return c.CheckEnvironmentYaml(ctx, org, string(bs))
+}
+
 // DecryptEnvironment decrypts the environment with the given name in the given organization.
+// If the client's configuration sets SecretDecryptFunc, each `fn::secret` value that was encrypted with
+// SecretEncryptFunc is also decrypted with it, and the returned YAML is re-rendered from the result, without the
+// original comments or key order. Secrets that were not encrypted on the client are returned as they are.
 func (c *EscClient) DecryptEnvironment(ctx context.Context, org, envName string) (*EnvironmentDefinition, string, error) {
-	env, resp, err := c.EscAPI.DecryptEnvironment(ctx, org, envName).Execute()
+	env, body, err := c.decryptEnvironmentYaml(ctx, org, envName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if c.rawClient.cfg.SecretDecryptFunc == nil {
+		return env, string(body), nil
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return nil, "", err
+	}
+	if doc, err = c.decryptSecrets(doc); err != nil {
+		return nil, "", err
+	}
+	bs, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, "", err
+	}
 
-	body, bodyErr := io.ReadAll(resp.Body)
+	var decrypted EnvironmentDefinition
+	if err := yaml.Unmarshal(bs, &decrypted); err != nil {
+		return nil, "", err
+	}
+	return &decrypted, string(bs), nil
+}
+
+// decryptEnvironmentYaml decrypts the environment with the given name in the given organization with the service
+// only, returning the definition and its YAML. Secrets encrypted with the configuration's SecretEncryptFunc are left
+// encrypted.
+func (c *EscClient) decryptEnvironmentYaml(ctx context.Context, org, envName string) (*EnvironmentDefinition, []byte, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, nil, err
+	}
+
+	env, resp, err := c.EscAPI.DecryptEnvironment(ctx, org, envName).Execute()
 	if err != nil {
-		return nil, "", bodyErr
+		return nil, nil, wrapNotFound(err, resp, org, envName)
 	}
 
-	return env, string(body), err
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return env, body, nil
 }
 
 // ListEnvironmentRevisions lists all revisions of the environment with the given name in the given organization.
 func (c *EscClient) ListEnvironmentRevisions(ctx context.Context, org, envName string) ([]EnvironmentRevision, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
 	request := c.EscAPI.ListEnvironmentRevisions(ctx, org, envName)
 
 	revs, _, err := request.Execute()
@@ -219,14 +761,51 @@ func (c *EscClient) ListEnvironmentRevisions(ctx context.Context, org, envName s
 
 // ListEnvironmentRevisionsPaginated lists all revisions of the environment with the given name in the given organization, with pagination support.
 func (c *EscClient) ListEnvironmentRevisionsPaginated(ctx context.Context, org, envName string, before, count int32) ([]EnvironmentRevision, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
 	request := c.EscAPI.ListEnvironmentRevisions(ctx, org, envName).Before(before).Count(count)
 
 	revs, _, err := request.Execute()
 	return revs, err
 }
 
+// ListAllEnvironmentRevisions lists every revision of the environment with the given name in the given organization,
+// paging backwards through the revision history until the first revision has been read.
+func (c *EscClient) ListAllEnvironmentRevisions(ctx context.Context, org, envName string) ([]EnvironmentRevision, error) {
+	revs, err := c.ListEnvironmentRevisions(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	all := revs
+	for len(revs) > 0 {
+		oldest := revs[0].Number
+		for _, rev := range revs {
+			if rev.Number < oldest {
+				oldest = rev.Number
+			}
+		}
+		if oldest <= 1 {
+			break
+		}
+
+		revs, _, err = c.EscAPI.ListEnvironmentRevisions(ctx, org, envName).Before(oldest).Execute()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, revs...)
+	}
+	return all, nil
+}
+
 // ListEnvironmentRevisionTags lists all tags of the environment with the given name in the given organization.
 func (c *EscClient) ListEnvironmentRevisionTags(ctx context.Context, org, envName string) (*EnvironmentRevisionTags, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
 	request := c.EscAPI.client.EscAPI.ListEnvironmentRevisionTags(ctx, org, envName)
 
 	revs, _, err := request.Execute()
@@ -235,6 +814,10 @@ func (c *EscClient) ListEnvironmentRevisionTags(ctx context.Context, org, envNam
 
 // ListEnvironmentRevisionTagsPaginated lists all tags of the environment with the given name in the given organization, with pagination support.
 func (c *EscClient) ListEnvironmentRevisionTagsPaginated(ctx context.Context, org, envName string, after string, count int32) (*EnvironmentRevisionTags, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
 	request := c.EscAPI.ListEnvironmentRevisionTags(ctx, org, envName).After(after).Count(count)
 
 	tags, _, err := request.Execute()
@@ -243,6 +826,10 @@ func (c *EscClient) ListEnvironmentRevisionTagsPaginated(ctx context.Context, or
 
 // GetEnvironmentRevisionTag retrieves the tag with the given name of the environment with the given name in the given organization.
 func (c *EscClient) GetEnvironmentRevisionTag(ctx context.Context, org, envName, tagName string) (*EnvironmentRevisionTag, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
 	request := c.EscAPI.client.EscAPI.GetEnvironmentRevisionTag(ctx, org, envName, tagName)
 
 	revision, _, err := request.Execute()
@@ -251,6 +838,10 @@ func (c *EscClient) GetEnvironmentRevisionTag(ctx context.Context, org, envName,
 
 // CreateEnvironmentRevisionTag creates a new tag with the given name for the environment with the given name in the given organization.
 func (c *EscClient) CreateEnvironmentRevisionTag(ctx context.Context, org, envName, tagName string, revision int32) error {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return err
+	}
+
 	update := NewUpdateEnvironmentRevisionTag(revision)
 	request := c.EscAPI.client.EscAPI.CreateEnvironmentRevisionTag(ctx, org, envName, tagName).UpdateEnvironmentRevisionTag(*update)
 
@@ -260,6 +851,10 @@ func (c *EscClient) CreateEnvironmentRevisionTag(ctx context.Context, org, envNa
 
 // UpdateEnvironmentRevisionTag updates the tag's revision with the given name for the environment with the given name in the given organization.
 func (c *EscClient) UpdateEnvironmentRevisionTag(ctx context.Context, org, envName, tagName string, revision int32) error {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return err
+	}
+
 	update := NewUpdateEnvironmentRevisionTag(revision)
 	request := c.EscAPI.client.EscAPI.UpdateEnvironmentRevisionTag(ctx, org, envName, tagName).UpdateEnvironmentRevisionTag(*update)
 
@@ -267,14 +862,160 @@ func (c *EscClient) UpdateEnvironmentRevisionTag(ctx context.Context, org, envNa
 	return err
 }
 
+// UpsertEnvironmentRevisionTag creates a tag with the given name pointing at the given revision of the environment with
+// the given name in the given organization, or updates the tag to point at the revision if it already exists.
+// The resulting tag is returned.
+func (c *EscClient) UpsertEnvironmentRevisionTag(ctx context.Context, org, envName, tagName string, revision int32) (*EnvironmentRevisionTag, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
+	update := NewUpdateEnvironmentRevisionTag(revision)
+	resp, err := c.EscAPI.CreateEnvironmentRevisionTag(ctx, org, envName, tagName).UpdateEnvironmentRevisionTag(*update).Execute()
+	if err != nil {
+		if resp == nil || resp.StatusCode != http.StatusConflict {
+			return nil, wrapNotFound(err, resp, org, envName)
+		}
+		if err := c.UpdateEnvironmentRevisionTag(ctx, org, envName, tagName, revision); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.GetEnvironmentRevisionTag(ctx, org, envName, tagName)
+}
+
+// PromoteRevisionTag points the tag with the given name at the latest revision of the environment with the given name
+// in the given organization, creating the tag if it does not exist.
+func (c *EscClient) PromoteRevisionTag(ctx context.Context, org, envName, tagName string) error {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return err
+	}
+
+	revs, resp, err := c.EscAPI.ListEnvironmentRevisions(ctx, org, envName).Count(1).Execute()
+	if err != nil {
+		return wrapNotFound(err, resp, org, envName)
+	}
+	if len(revs) == 0 {
+		return fmt.Errorf("environment %s/%s has no revisions", org, envName)
+	}
+
+	latest := revs[0].Number
+	for _, rev := range revs {
+		if rev.Number > latest {
+			latest = rev.Number
+		}
+	}
+
+	_, err = c.UpsertEnvironmentRevisionTag(ctx, org, envName, tagName, latest)
+	return err
+}
+
 // DeleteEnvironmentRevisionTag deletes the tag with the given name for the environment with the given name in the given organization.
 func (c *EscClient) DeleteEnvironmentRevisionTag(ctx context.Context, org, envName, tagName string) error {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return err
+	}
+
 	request := c.EscAPI.client.EscAPI.DeleteEnvironmentRevisionTag(ctx, org, envName, tagName)
 
 	_, err := request.Execute()
 	return err
 }
 
+// TokenInfo describes the access token used to authenticate requests.
+type TokenInfo struct {
+	// Valid is false if the token was rejected as expired, revoked or malformed.
+	Valid bool
+	// UserLogin is the login of the user or machine account that owns the token.
+	UserLogin string
+	// UserName is the display name of the user or machine account that owns the token.
+	UserName string
+	// Organizations lists the organizations the token's owner belongs to.
+	Organizations []string
+}
+
+// ValidateToken checks the access token in the given context against the Pulumi Cloud user endpoint.
+// A rejected token (401) is reported through TokenInfo.Valid rather than as an error,
+// so that errors are reserved for network and server failures.
+func (c *EscClient) ValidateToken(ctx context.Context) (*TokenInfo, error) {
+	basePath, err := c.rawClient.cfg.ServerURLWithContext(ctx, "EscAPIService.ValidateToken")
+	if err != nil {
+		return nil, err
+	}
+
+	// The user endpoint lives outside of the preview API.
+	path := strings.TrimSuffix(basePath, "/preview") + "/user"
+	resp, body, err := c.rawRequest(ctx, "ValidateToken", http.MethodGet, path, nil, nil)
+	if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+		return &TokenInfo{Valid: false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		GithubLogin   string `json:"githubLogin"`
+		Name          string `json:"name"`
+		Organizations []struct {
+			GithubLogin string `json:"githubLogin"`
+		} `json:"organizations"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, err
+	}
+
+	info := &TokenInfo{
+		Valid:     true,
+		UserLogin: user.GithubLogin,
+		UserName:  user.Name,
+	}
+	for _, org := range user.Organizations {
+		info.Organizations = append(info.Organizations, org.GithubLogin)
+	}
+	return info, nil
+}
+
+// GetDefaultOrganization returns the organization to use when none is given explicitly.
+// The PULUMI_ORG environment variable takes precedence; otherwise the default organization of the account
+// that owns the access token in the given context is returned, falling back to the account's own name.
+func (c *EscClient) GetDefaultOrganization(ctx context.Context) (string, error) {
+	if org := os.Getenv("PULUMI_ORG"); org != "" {
+		return org, nil
+	}
+
+	basePath, err := c.rawClient.cfg.ServerURLWithContext(ctx, "EscAPIService.GetDefaultOrganization")
+	if err != nil {
+		return "", err
+	}
+
+	// The user endpoints live outside of the preview API.
+	path := strings.TrimSuffix(basePath, "/preview") + "/user/organizations/default"
+	resp, body, err := c.rawRequest(ctx, "GetDefaultOrganization", http.MethodGet, path, nil, nil)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return "", err
+	}
+	if err == nil {
+		var defaultOrg struct {
+			GitHubLogin string `json:"gitHubLogin"`
+		}
+		if err := json.Unmarshal(body, &defaultOrg); err != nil {
+			return "", err
+		}
+		if defaultOrg.GitHubLogin != "" {
+			return defaultOrg.GitHubLogin, nil
+		}
+	}
+
+	info, err := c.ValidateToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !info.Valid {
+		return "", errors.New("the access token is invalid")
+	}
+	return info.UserLogin, nil
+}
+
 func MarshalEnvironmentDefinition(env *EnvironmentDefinition) (string, error) {
 	var bs []byte
 	bs, err := yaml.Marshal(env)
@@ -285,6 +1026,54 @@ func MarshalEnvironmentDefinition(env *EnvironmentDefinition) (string, error) {
 	return "", err
 }
 
+// MarshalEnvironmentDefinitionOrdered marshals the given definition to YAML in a canonical form with all object keys
+// sorted, so that semantically equal definitions always produce identical output.
+func MarshalEnvironmentDefinitionOrdered(env *EnvironmentDefinition) (string, error) {
+	normalized, err := normalizeDefinition(env)
+	if err != nil {
+		return "", err
+	}
+
+	bs, err := yamlv2.Marshal(orderedYAML(normalized))
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+// orderedYAML converts objects within the given value into YAML map slices with sorted keys.
+func orderedYAML(value any) any {
+	switch val := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		output := make(yamlv2.MapSlice, 0, len(keys))
+		for _, k := range keys {
+			output = append(output, yamlv2.MapItem{Key: k, Value: orderedYAML(val[k])})
+		}
+		return output
+	case []any:
+		output := make([]any, len(val))
+		for i, v := range val {
+			output[i] = orderedYAML(v)
+		}
+		return output
+	default:
+		return value
+	}
+}
+
+// resolvePropertyValue decodes the nested values of a property read from an open environment in place, as
+// ReadOpenEnvironment does for each property, and returns its resolved value as plain Go values.
+func resolvePropertyValue(prop *Value) any {
+	prop.Value = mapValues(prop.Value)
+	return mapValuesPrimitive(prop.Value)
+}
+
 func mapValuesPrimitive(value any) any {
 	switch val := value.(type) {
 	case *Value:
@@ -297,10 +1086,11 @@ func mapValuesPrimitive(value any) any {
 
 		return output
 	case []any:
+		output := make([]any, len(val))
 		for i, v := range val {
-			val[i] = mapValuesPrimitive(v)
+			output[i] = mapValuesPrimitive(v)
 		}
-		return val
+		return output
 	default:
 		return value
 	}
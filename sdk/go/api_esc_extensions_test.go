@@ -0,0 +1,448 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UpdateEnvironmentYamlEmpty(t *testing.T) {
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+	})
+
+	for _, yaml := range []string{"", " \n\t\n"} {
+		_, err := apiClient.UpdateEnvironmentYaml(NewAuthContext("pul-123"), "test-org", "test-env", yaml)
+		require.ErrorIs(t, err, ErrEmptyEnvironmentYaml)
+	}
+}
+
+func Test_UpsertEnvironmentRevisionTag(t *testing.T) {
+	var methods []string
+	tags := map[string]int32{"stable": 1}
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		name := path.Base(r.URL.Path)
+
+		var update UpdateEnvironmentRevisionTag
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&update)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			if _, ok := tags[name]; ok {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"code": 409, "message": "tag already exists"}`))
+				return
+			}
+			tags[name] = update.Revision
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodPatch:
+			tags[name] = update.Revision
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(EnvironmentRevisionTag{Name: name, Revision: tags[name]})
+		}
+	})
+	auth := NewAuthContext("pul-123")
+
+	tag, err := apiClient.UpsertEnvironmentRevisionTag(auth, "test-org", "test-env", "latest", 3)
+	require.Nil(t, err)
+	require.Equal(t, EnvironmentRevisionTag{Name: "latest", Revision: 3}, *tag)
+	require.Equal(t, []string{http.MethodPost, http.MethodGet}, methods)
+
+	methods = nil
+	tag, err = apiClient.UpsertEnvironmentRevisionTag(auth, "test-org", "test-env", "stable", 2)
+	require.Nil(t, err)
+	require.Equal(t, EnvironmentRevisionTag{Name: "stable", Revision: 2}, *tag)
+	require.Equal(t, []string{http.MethodPost, http.MethodPatch, http.MethodGet}, methods)
+}
+
+func Test_PromoteRevisionTag(t *testing.T) {
+	var requests []string
+	latest := map[string]int32{"test-env": 4, "empty-env": 0}
+	tags := map[string]int32{"stable": 2}
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+
+		w.Header().Set("Content-Type", "application/json")
+		if envName := path.Base(path.Dir(r.URL.Path)); path.Base(r.URL.Path) == "versions" {
+			n, ok := latest[envName]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code": 404, "message": "not found"}`))
+				return
+			}
+			revs := []EnvironmentRevision{}
+			if n > 0 {
+				revs = append(revs, EnvironmentRevision{Number: n})
+			}
+			_ = json.NewEncoder(w).Encode(revs)
+			return
+		}
+
+		name := path.Base(r.URL.Path)
+		var update UpdateEnvironmentRevisionTag
+		_ = json.NewDecoder(r.Body).Decode(&update)
+		switch r.Method {
+		case http.MethodPost:
+			if _, ok := tags[name]; ok {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"code": 409, "message": "tag already exists"}`))
+				return
+			}
+			tags[name] = update.Revision
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodPatch:
+			tags[name] = update.Revision
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(EnvironmentRevisionTag{Name: name, Revision: tags[name]})
+		}
+	})
+	auth := NewAuthContext("pul-123")
+
+	err := apiClient.PromoteRevisionTag(auth, "test-org", "test-env", "prod")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), tags["prod"])
+	require.Equal(t, []string{
+		"GET /environments/test-org/test-env/versions?count=1",
+		"POST /environments/test-org/test-env/versions/tags/prod",
+		"GET /environments/test-org/test-env/versions/tags/prod",
+	}, requests)
+
+	requests = nil
+	err = apiClient.PromoteRevisionTag(auth, "test-org", "test-env", "stable")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), tags["stable"])
+	require.Equal(t, []string{
+		"GET /environments/test-org/test-env/versions?count=1",
+		"POST /environments/test-org/test-env/versions/tags/stable",
+		"PATCH /environments/test-org/test-env/versions/tags/stable",
+		"GET /environments/test-org/test-env/versions/tags/stable",
+	}, requests)
+
+	err = apiClient.PromoteRevisionTag(auth, "test-org", "empty-env", "prod")
+	require.EqualError(t, err, "environment test-org/empty-env has no revisions")
+
+	err = apiClient.PromoteRevisionTag(auth, "test-org", "missing", "prod")
+	require.ErrorIs(t, err, ErrEnvironmentNotFound)
+}
+
+func Test_OpenEnvironmentWithDiagnostics(t *testing.T) {
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if path.Base(path.Dir(r.URL.Path)) == "invalid-env" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"diagnostics": [{"summary": "unknown property"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "1", "diagnostics": {"diagnostics": [{"summary": "stale credentials"}]}}`))
+	})
+	auth := NewAuthContext("pul-123")
+
+	openEnv, diags, err := apiClient.OpenEnvironmentWithDiagnostics(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "1", openEnv.Id)
+	require.Len(t, diags, 1)
+	require.Equal(t, "stale credentials", diags[0].Summary)
+
+	_, diags, err = apiClient.OpenEnvironmentWithDiagnostics(auth, "test-org", "invalid-env")
+	require.Error(t, err)
+	require.Len(t, diags, 1)
+	require.Equal(t, "unknown property", diags[0].Summary)
+}
+
+func Test_ReadEnvironmentPropertyPath(t *testing.T) {
+	var requestPath, property string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requestPath, property = r.URL.EscapedPath(), r.URL.Query().Get("property")
+		w.Header().Set("Content-Type", "application/json")
+		switch property {
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 404, "message": "not found"}`))
+		case "object":
+			_, _ = w.Write([]byte(`{"value": {"a": {"value": 1, "trace": ` + testTrace + `}}, "trace": ` + testTrace + `}`))
+		default:
+			_, _ = w.Write([]byte(`{"value": "v", "trace": ` + testTrace + `}`))
+		}
+	})
+	auth := NewAuthContext("pul-123")
+
+	for _, propPath := range []string{"a/b", "a.b", "list[0]", `a["b c"]`, "a?b&c=d#e", "100%"} {
+		t.Run(propPath, func(t *testing.T) {
+			_, value, err := apiClient.ReadEnvironmentProperty(auth, "test-org", "test-env", "session/1", propPath)
+			require.Nil(t, err)
+			require.Equal(t, "v", value)
+			require.Equal(t, "/environments/test-org/test-env/open//session%2F1", requestPath)
+			require.Equal(t, propPath, property)
+		})
+	}
+
+	_, _, err := apiClient.ReadEnvironmentProperty(auth, "test-org", "test-env", "session/1", "missing")
+	require.Error(t, err)
+
+	// Object values decode the same way as with ResolveProperty.
+	_, value, err := apiClient.ReadEnvironmentProperty(auth, "test-org", "test-env", "session/1", "object")
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{"a": 1.0}, value)
+}
+
+func Test_GetEnvironmentRaw(t *testing.T) {
+	const definition = "# Shared settings.\nvalues:\n  region:   us-west-2 # primary\n"
+	apiClient := newTestDefinitionServer(t, map[string]string{"app": definition})
+
+	raw, err := apiClient.GetEnvironmentRaw(NewAuthContext("pul-123"), "test-org", "app")
+	require.Nil(t, err)
+	require.Equal(t, definition, raw)
+
+	_, err = apiClient.GetEnvironmentRaw(NewAuthContext("pul-123"), "test-org", "missing")
+	require.ErrorIs(t, err, ErrEnvironmentNotFound)
+}
+
+func Test_GetEnvironmentJSON(t *testing.T) {
+	var accept []string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		accept = append(accept, r.Header.Get("Accept"))
+		switch r.URL.Path {
+		case "/environments/test-org/json-env":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"values":{"region":"us-west-2"}}`))
+		case "/environments/test-org/yaml-env":
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = w.Write([]byte("imports:\n  - base\nvalues:\n  region: us-east-1\n"))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 404, "message": "not found"}`))
+		}
+	})
+	auth := NewAuthContext("pul-123")
+
+	env, raw, err := apiClient.GetEnvironmentJSON(auth, "test-org", "json-env")
+	require.Nil(t, err)
+	require.JSONEq(t, `{"values":{"region":"us-west-2"}}`, string(raw))
+	require.Equal(t, "us-west-2", env.Values.AdditionalProperties["region"])
+
+	env, raw, err = apiClient.GetEnvironmentJSON(auth, "test-org", "yaml-env")
+	require.Nil(t, err)
+	require.JSONEq(t, `{"imports":["base"],"values":{"region":"us-east-1"}}`, string(raw))
+	require.Equal(t, []string{"base"}, env.Imports)
+	require.Equal(t, "us-east-1", env.Values.AdditionalProperties["region"])
+
+	_, _, err = apiClient.GetEnvironmentJSON(auth, "test-org", "missing")
+	require.ErrorIs(t, err, ErrEnvironmentNotFound)
+
+	require.Equal(t, []string{"application/json", "application/json", "application/json"}, accept)
+}
+
+func Test_GetEnvironmentETag(t *testing.T) {
+	var requests []string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if path.Base(r.URL.Path) != "test-env" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"rev-3"`)
+	})
+	auth := NewAuthContext("pul-123")
+
+	etag, err := apiClient.GetEnvironmentETag(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, `"rev-3"`, etag)
+
+	_, err = apiClient.GetEnvironmentETag(auth, "test-org", "missing")
+	require.ErrorIs(t, err, ErrEnvironmentNotFound)
+
+	require.Equal(t, []string{
+		"HEAD /environments/test-org/test-env",
+		"HEAD /environments/test-org/missing",
+	}, requests)
+}
+
+func Test_CreateEnvironmentIfNotExists(t *testing.T) {
+	existing := map[string]bool{"existing-env": true}
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		name := path.Base(r.URL.Path)
+		if existing[name] {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"code": 409, "message": "environment already exists"}`))
+			return
+		}
+		existing[name] = true
+		_, _ = w.Write([]byte(`{"code": 200, "message": "created"}`))
+	})
+	auth := NewAuthContext("pul-123")
+
+	created, err := apiClient.CreateEnvironmentIfNotExists(auth, "test-org", "new-env")
+	require.Nil(t, err)
+	require.True(t, created)
+
+	created, err = apiClient.CreateEnvironmentIfNotExists(auth, "test-org", "existing-env")
+	require.Nil(t, err)
+	require.False(t, created)
+
+	err = apiClient.CreateEnvironment(auth, "test-org", "existing-env")
+	require.ErrorIs(t, err, ErrEnvironmentExists)
+	require.ErrorContains(t, err, "test-org/existing-env")
+}
+
+func Test_ListEnvironmentsModifiedSince(t *testing.T) {
+	pages := map[string]string{
+		"": `{"environments": [
+			{"name": "recent", "created": "2024-01-01T00:00:00Z", "modified": "2024-03-01T00:00:00Z"},
+			{"name": "stale", "created": "2024-01-01T00:00:00Z", "modified": "2024-01-15 10:00:00"}
+		], "nextToken": "page-2"}`,
+		"page-2": `{"environments": [
+			{"name": "unknown-recent", "created": "2024-01-01T00:00:00Z", "modified": ""},
+			{"name": "unknown-stale", "created": "2024-01-01T00:00:00Z", "modified": "yesterday"}
+		], "nextToken": "page-3"}`,
+		"page-3": `{"environments": []}`,
+	}
+	revisions := map[string]string{
+		"unknown-recent": "2024-04-01T00:00:00Z",
+		"unknown-stale":  "2023-12-01T00:00:00Z",
+	}
+
+	var mu sync.Mutex
+	var requests []string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/environments/test-org" {
+			_, _ = w.Write([]byte(pages[r.URL.Query().Get("continuationToken")]))
+			return
+		}
+		created := revisions[path.Base(path.Dir(r.URL.Path))]
+		_ = json.NewEncoder(w).Encode([]EnvironmentRevision{{Number: 1, Created: &created}})
+	})
+
+	since := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	envs, err := apiClient.ListEnvironmentsModifiedSince(NewAuthContext("pul-123"), "test-org", since)
+	require.Nil(t, err)
+	names := make([]string, len(envs))
+	for i, env := range envs {
+		names[i] = env.Name
+	}
+	require.Equal(t, []string{"recent", "unknown-recent"}, names)
+	require.ElementsMatch(t, []string{
+		"GET /environments/test-org",
+		"GET /environments/test-org?continuationToken=page-2",
+		"GET /environments/test-org?continuationToken=page-3",
+		"GET /environments/test-org/unknown-recent/versions?count=1",
+		"GET /environments/test-org/unknown-stale/versions?count=1",
+	}, requests)
+
+	revisions["unknown-stale"] = "last week"
+	_, err = apiClient.ListEnvironmentsModifiedSince(NewAuthContext("pul-123"), "test-org", since)
+	require.EqualError(t, err, `environment unknown-stale: revision 1: unrecognized revision timestamp "last week"`)
+}
+
+func Test_UpdateEnvironmentDryRun(t *testing.T) {
+	defs := map[string]string{"base": "imports:\n  - test-env\nvalues:\n  region: us-west-2\n"}
+	var requests []string
+	var checked string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.Method == http.MethodPost && r.URL.Path == "/environments/test-org/yaml/check" {
+			checked = string(body)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"diagnostics": [{"summary": "unknown property"}]}`))
+			return
+		}
+		if def, ok := defs[path.Base(r.URL.Path)]; ok && r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = w.Write([]byte(def))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	auth := NewAuthContext("pul-123")
+
+	env := &EnvironmentDefinition{
+		Imports: []string{"base"},
+		Values:  &EnvironmentDefinitionValues{AdditionalProperties: map[string]any{"region": "us-east-1"}},
+	}
+	expected, err := MarshalEnvironmentDefinition(env)
+	require.Nil(t, err)
+
+	check, err := apiClient.UpdateEnvironmentDryRun(auth, "test-org", "test-env", env)
+	require.Nil(t, err)
+	require.Equal(t, "unknown property", check.Diagnostics[0].Summary)
+	require.Equal(t, expected, checked)
+	require.Equal(t, []string{"POST /environments/test-org/yaml/check"}, requests)
+
+	requests = nil
+	apiClient.rawClient.cfg.DetectImportCycles = true
+	_, err = apiClient.UpdateEnvironmentDryRun(auth, "test-org", "test-env", env)
+	var cycleErr *ImportCycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Equal(t, []string{"GET /environments/test-org/base"}, requests)
+
+	apiClient.rawClient.cfg.ValidateNames = true
+	_, err = apiClient.UpdateEnvironmentDryRun(auth, "test-org", "a/b", env)
+	require.ErrorIs(t, err, ErrInvalidName)
+	require.Len(t, requests, 1)
+}
+
+func Test_ResolveProperty(t *testing.T) {
+	var opened, property string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			opened = r.URL.Path
+			_, _ = w.Write([]byte(`{"id": "1"}`))
+			return
+		}
+		property = r.URL.Query().Get("property")
+		_, _ = w.Write([]byte(`{"value": {"host": {"value": "db.internal", "trace": ` + testTrace + `}}, "trace": ` + testTrace + `}`))
+	})
+
+	value, err := apiClient.ResolveProperty(NewAuthContext("pul-123"), "test-org", "test-env", "db")
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{"host": "db.internal"}, value)
+	require.Equal(t, "/environments/test-org/test-env/open", opened)
+	require.Equal(t, "db", property)
+}
+
+func Test_NewClientWithOptions(t *testing.T) {
+	var header http.Header
+	var requestPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header, requestPath = r.Header, r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"environments": []}`))
+	}))
+	t.Cleanup(server.Close)
+
+	apiClient := NewClientWithOptions(server.URL+"/api/esc/", server.Client(),
+		WithDefaultHeader("X-Test", "yes"),
+		WithTokenProvider(func(context.Context) (string, error) { return "pul-456", nil }))
+
+	_, err := apiClient.ListEnvironments(context.Background(), "test-org", nil)
+	require.Nil(t, err)
+	require.Equal(t, "/api/esc/environments/test-org", requestPath)
+	require.Equal(t, "yes", header.Get("X-Test"))
+	require.Equal(t, "token pul-456", header.Get("Authorization"))
+}
@@ -0,0 +1,132 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RequestTimeoutError is returned by the client's hand-written requests when a request times out, either because the
+// configuration's RequestTimeout elapsed or because the context's deadline passed. It unwraps to the underlying error,
+// so it also matches context.DeadlineExceeded when the context's deadline passed.
+type RequestTimeoutError struct {
+	// Operation is the name of the operation that timed out, e.g. "UpdateEnvironmentYaml".
+	Operation string
+	// Timeout is the time the request was allowed to take, or zero if it is not known, e.g. because the timeout was set
+	// on the HTTP client itself.
+	Timeout time.Duration
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *RequestTimeoutError) Error() string {
+	if e.Timeout <= 0 {
+		return e.Operation + ": request timed out"
+	}
+	return fmt.Sprintf("%s: request timed out after %v", e.Operation, e.Timeout)
+}
+
+func (e *RequestTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// rawRequest sends a request to an endpoint that is not covered by the generated API client.
+// The response body is read and returned alongside the response; statuses of 300 and above
+// are returned as a GenericOpenAPIError, mirroring the generated operations.
+func (c *EscClient) rawRequest(ctx context.Context, operation, method, path string, headers map[string]string, body any) (*http.Response, []byte, error) {
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	if _, ok := headers["Accept"]; !ok {
+		headers["Accept"] = "application/json"
+	}
+	headers["X-Pulumi-Source"] = "esc-sdk"
+	setAuthorizationHeader(ctx, headers)
+
+	req, err := c.rawClient.prepareRequest(ctx, path, method, body, headers, url.Values{}, url.Values{}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start := time.Now()
+	resp, err := c.rawClient.callAPI(req, operation)
+	if err != nil || resp == nil {
+		return resp, nil, c.wrapTimeout(ctx, operation, start, err)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewBuffer(respBody))
+	if err != nil {
+		return resp, nil, c.wrapTimeout(ctx, operation, start, err)
+	}
+
+	if resp.StatusCode >= 300 {
+		newErr := &GenericOpenAPIError{
+			body:  respBody,
+			error: resp.Status,
+		}
+		var v Error
+		if err := c.rawClient.decode(&v, respBody, resp.Header.Get("Content-Type")); err == nil {
+			newErr.error = formatErrorMessage(resp.Status, &v)
+			newErr.model = v
+		}
+		return resp, respBody, newErr
+	}
+
+	return resp, respBody, nil
+}
+
+// wrapTimeout converts an error caused by a request timing out into a RequestTimeoutError that names the operation.
+// The timeout reported is the time the context allowed if its deadline passed, and RequestTimeout otherwise.
+// Any other error is returned unchanged.
+func (c *EscClient) wrapTimeout(ctx context.Context, operation string, start time.Time, err error) error {
+	var netErr net.Error
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) && !(errors.As(err, &netErr) && netErr.Timeout()) {
+		return err
+	}
+
+	timeout := c.rawClient.cfg.RequestTimeout
+	if deadline, ok := ctx.Deadline(); ok && ctx.Err() != nil {
+		timeout = deadline.Sub(start).Round(time.Millisecond)
+	}
+	return &RequestTimeoutError{Operation: operation, Timeout: timeout, Err: err}
+}
+
+// environmentURL returns the URL of the given environment, resolved against the server configured for the given operation.
+// The names are validated first if the client's configuration enables ValidateNames.
+func (c *EscClient) environmentURL(ctx context.Context, operation, org, envName string) (string, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return "", err
+	}
+
+	basePath, err := c.rawClient.cfg.ServerURLWithContext(ctx, "EscAPIService."+operation)
+	if err != nil {
+		return "", err
+	}
+	return basePath + "/environments/" + url.PathEscape(org) + "/" + url.PathEscape(envName), nil
+}
+
+// setAuthorizationHeader sets the Authorization header from the API key stored in the context, if any.
+func setAuthorizationHeader(ctx context.Context, headers map[string]string) {
+	if ctx == nil {
+		return
+	}
+	if auth, ok := ctx.Value(ContextAPIKeys).(map[string]APIKey); ok {
+		if apiKey, ok := auth["Authorization"]; ok {
+			if apiKey.Prefix != "" {
+				headers["Authorization"] = apiKey.Prefix + " " + apiKey.Key
+			} else {
+				headers["Authorization"] = apiKey.Key
+			}
+		}
+	}
+}
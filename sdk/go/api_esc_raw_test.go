@@ -0,0 +1,116 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AuthorizationHeader(t *testing.T) {
+	var authorization []string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Values("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	})
+	serverURL := apiClient.rawClient.cfg.Servers[0].URL
+
+	tests := []struct {
+		prefix   string
+		expected string
+	}{
+		{prefix: "", expected: "pul-123"},
+		{prefix: "token", expected: "token pul-123"},
+		{prefix: "Bearer", expected: "Bearer pul-123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			auth := NewAuthContextWithPrefix("pul-123", tt.prefix)
+
+			_, err := apiClient.OpenEnvironment(auth, "test-org", "test-env")
+			require.Nil(t, err)
+			require.Equal(t, []string{tt.expected}, authorization)
+
+			_, _, err = apiClient.rawRequest(auth, "Test", http.MethodGet, serverURL, nil, nil)
+			require.Nil(t, err)
+			require.Equal(t, []string{tt.expected}, authorization)
+		})
+	}
+}
+
+func Test_RawRequestCancellation(t *testing.T) {
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	})
+	serverURL := apiClient.rawClient.cfg.Servers[0].URL
+
+	tests := map[string]func(ctx context.Context) error{
+		"rawRequest": func(ctx context.Context) error {
+			_, _, err := apiClient.rawRequest(ctx, "Test", http.MethodGet, serverURL, nil, nil)
+			return err
+		},
+		"ValidateToken": func(ctx context.Context) error {
+			_, err := apiClient.ValidateToken(ctx)
+			return err
+		},
+		"SetEnvironmentValue": func(ctx context.Context) error {
+			_, err := apiClient.SetEnvironmentValue(ctx, "test-org", "test-env", "foo", "bar")
+			return err
+		},
+	}
+	for name, call := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(NewAuthContext("pul-123"), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := call(ctx)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+			require.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
+
+func Test_RawRequestTimeout(t *testing.T) {
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// The server only notices that the client has gone away once the request body has been read.
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}, func(cfg *Configuration) {
+		cfg.RequestTimeout = 50 * time.Millisecond
+	})
+	serverURL := apiClient.rawClient.cfg.Servers[0].URL
+
+	_, _, err := apiClient.rawRequest(NewAuthContext("pul-123"), "UpdateEnvironmentYaml", http.MethodPatch, serverURL, nil, "values: {}")
+	var timeoutErr *RequestTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	require.Equal(t, "UpdateEnvironmentYaml", timeoutErr.Operation)
+	require.EqualError(t, err, "UpdateEnvironmentYaml: request timed out after 50ms")
+
+	apiClient.rawClient.cfg.RequestTimeout = 0
+	ctx, cancel := context.WithTimeout(NewAuthContext("pul-123"), 100*time.Millisecond)
+	defer cancel()
+	_, _, err = apiClient.rawRequest(ctx, "GetEnvironmentRaw", http.MethodGet, serverURL, nil, nil)
+	require.ErrorAs(t, err, &timeoutErr)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, "GetEnvironmentRaw", timeoutErr.Operation)
+	require.InDelta(t, 100*time.Millisecond, timeoutErr.Timeout, float64(10*time.Millisecond))
+
+	apiClient.rawClient.cfg.HTTPClient = &http.Client{Timeout: 50 * time.Millisecond}
+	_, _, err = apiClient.rawRequest(NewAuthContext("pul-123"), "GetEnvironmentRaw", http.MethodGet, serverURL, nil, nil)
+	require.ErrorAs(t, err, &timeoutErr)
+	require.Zero(t, timeoutErr.Timeout)
+	require.EqualError(t, err, "GetEnvironmentRaw: request timed out")
+}
@@ -26,16 +26,17 @@ const ENV_PREFIX = "sdk-go-test-"
 func Test_EscClient(t *testing.T) {
 	accessToken := os.Getenv("PULUMI_ACCESS_TOKEN")
 	require.NotEmpty(t, accessToken, "PULUMI_ACCESS_TOKEN must be set")
-	orgName := os.Getenv("PULUMI_ORG")
-	require.NotEmpty(t, orgName, "PULUMI_ORG must be set")
 	configuration := NewConfiguration()
 	apiClient := NewClient(configuration)
 	auth := NewAuthContext(accessToken)
+	orgName, err := apiClient.GetDefaultOrganization(auth)
+	require.Nil(t, err)
+	require.NotEmpty(t, orgName, "PULUMI_ORG must be set or the account must have a default organization")
 
 	removeAllGoTestEnvs(t, apiClient, auth, orgName)
 
 	baseEnvName := ENV_PREFIX + "base-" + time.Now().Format("20060102150405")
-	err := apiClient.CreateEnvironment(auth, orgName, baseEnvName)
+	err = apiClient.CreateEnvironment(auth, orgName, baseEnvName)
 	require.Nil(t, err)
 	t.Cleanup(func() {
 		err := apiClient.DeleteEnvironment(auth, orgName, baseEnvName)
@@ -173,6 +174,26 @@ values:
 
 	})
 
+	t.Run("should validate the access token", func(t *testing.T) {
+		info, err := apiClient.ValidateToken(auth)
+		require.Nil(t, err)
+		require.True(t, info.Valid)
+		require.Contains(t, info.Organizations, orgName)
+
+		info, err = apiClient.ValidateToken(NewAuthContext("pul-invalid"))
+		require.Nil(t, err)
+		require.False(t, info.Valid)
+	})
+
+	t.Run("should return ErrEnvironmentNotFound for a missing environment", func(t *testing.T) {
+		_, _, err := apiClient.GetEnvironment(auth, orgName, ENV_PREFIX+"does-not-exist")
+		require.ErrorIs(t, err, ErrEnvironmentNotFound)
+
+		var notFound *EnvironmentNotFoundError
+		require.ErrorAs(t, err, &notFound)
+		require.Equal(t, orgName, notFound.Org)
+	})
+
 	t.Run("check environment definition valid", func(t *testing.T) {
 		env := &EnvironmentDefinition{
 			Values: &EnvironmentDefinitionValues{
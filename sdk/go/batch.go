@@ -0,0 +1,100 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// EnvironmentRef identifies an environment, and optionally a version of it, within an organization.
+type EnvironmentRef struct {
+	EnvName string
+	// Version is a revision number or tag. If empty, the latest revision is used.
+	Version string
+}
+
+// ReadResult is the result of opening and reading a single environment in a batch.
+type ReadResult struct {
+	Environment *Environment
+	Values      map[string]any
+	Err         error
+}
+
+// OpenAndReadEnvironments opens and reads each of the referenced environments in the given organization concurrently.
+// A result is returned for every reference; failures to read individual environments are reported in their results
+// rather than failing the batch. If ctx is done before every environment has been read, the remaining references
+// are reported with ctx's error, which is also returned.
+func (c *EscClient) OpenAndReadEnvironments(ctx context.Context, org string, refs []EnvironmentRef) (map[EnvironmentRef]ReadResult, error) {
+	var mu sync.Mutex
+	results := make(map[EnvironmentRef]ReadResult, len(refs))
+	err := runBounded(ctx, refs, maxConcurrentReads, func(ctx context.Context, ref EnvironmentRef) error {
+		var result ReadResult
+		if ref.Version == "" {
+			result.Environment, result.Values, result.Err = c.OpenAndReadEnvironment(ctx, org, ref.EnvName)
+		} else {
+			result.Environment, result.Values, result.Err = c.OpenAndReadEnvironmentAtVersion(ctx, org, ref.EnvName, ref.Version)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		results[ref] = result
+		return nil
+	})
+	if err != nil {
+		for _, ref := range refs {
+			if _, ok := results[ref]; !ok {
+				results[ref] = ReadResult{Err: err}
+			}
+		}
+	}
+
+	return results, err
+}
+
+// CheckEnvironmentYamls checks each of the given environment YAML definitions, keyed by name, e.g. by file name, in
+// the given organization, running at most concurrency checks at once. A concurrency of zero or less runs one check at
+// a time. The check results and errors are returned keyed by the same names; as with CheckEnvironmentYaml, a definition
+// whose check failed may have both a result and an error. If ctx is done before every definition has been checked,
+// the remaining definitions are reported with ctx's error.
+func (c *EscClient) CheckEnvironmentYamls(
+	ctx context.Context,
+	org string,
+	yamls map[string]string,
+	concurrency int,
+) (map[string]*CheckEnvironment, map[string]error) {
+	names := make([]string, 0, len(yamls))
+	for name := range yamls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var mu sync.Mutex
+	results := make(map[string]*CheckEnvironment, len(yamls))
+	errs := make(map[string]error)
+	done := make(map[string]bool, len(yamls))
+	err := runBounded(ctx, names, concurrency, func(ctx context.Context, name string) error {
+		check, err := c.CheckEnvironmentYaml(ctx, org, yamls[name])
+
+		mu.Lock()
+		defer mu.Unlock()
+		done[name] = true
+		if check != nil {
+			results[name] = check
+		}
+		if err != nil {
+			errs[name] = err
+		}
+		return nil
+	})
+	if err != nil {
+		for _, name := range names {
+			if !done[name] {
+				errs[name] = err
+			}
+		}
+	}
+
+	return results, errs
+}
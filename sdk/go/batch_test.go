@@ -0,0 +1,81 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckEnvironmentYamls(t *testing.T) {
+	var running, maxRunning int32
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(string(body), "bad") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"diagnostics": [{"summary": "unknown property"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+	auth := NewAuthContext("pul-123")
+
+	yamls := map[string]string{"bad.yaml": "values:\n  bad: true\n"}
+	for i := 0; i < 8; i++ {
+		yamls[fmt.Sprintf("env-%d.yaml", i)] = "values:\n  ok: true\n"
+	}
+
+	results, errs := apiClient.CheckEnvironmentYamls(auth, "test-org", yamls, 3)
+	require.Len(t, results, len(yamls))
+	require.Len(t, errs, 1)
+	require.Error(t, errs["bad.yaml"])
+	require.Equal(t, "unknown property", results["bad.yaml"].Diagnostics[0].Summary)
+	require.LessOrEqual(t, maxRunning, int32(3))
+
+	ctx, cancel := context.WithCancel(auth)
+	cancel()
+	results, errs = apiClient.CheckEnvironmentYamls(ctx, "test-org", yamls, 3)
+	require.Empty(t, results)
+	require.Len(t, errs, len(yamls))
+	for _, err := range errs {
+		require.ErrorIs(t, err, context.Canceled)
+	}
+}
+
+func Test_OpenAndReadEnvironmentsCanceled(t *testing.T) {
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	ctx, cancel := context.WithCancel(NewAuthContext("pul-123"))
+	cancel()
+	refs := []EnvironmentRef{{EnvName: "app"}, {EnvName: "app", Version: "3"}, {EnvName: "db", Version: "stable"}}
+	results, err := apiClient.OpenAndReadEnvironments(ctx, "test-org", refs)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, results, len(refs))
+	for _, ref := range refs {
+		require.ErrorIs(t, results[ref].Err, context.Canceled, ref)
+		require.Nil(t, results[ref].Environment)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped, instead of sending a request while requests are being suspended after
+// repeated authentication failures. See Configuration.AuthFailureThreshold.
+var ErrCircuitOpen = errors.New("requests suspended after repeated authentication failures")
+
+// defaultAuthFailureCooldown is the cooldown used when AuthFailureThreshold is set but AuthFailureCooldown is not.
+const defaultAuthFailureCooldown = time.Minute
+
+// authBreaker is a circuit breaker that suspends requests after consecutive 401 Unauthorized or 403 Forbidden
+// responses, so that a revoked token does not cause a storm of doomed requests. Its zero value is closed.
+type authBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// allow returns an error wrapping ErrCircuitOpen if the breaker is open. Once the cooldown has passed, requests are
+// allowed again; if the next one also fails authentication, the breaker opens again immediately.
+func (b *authBreaker) allow(cfg *Configuration) error {
+	if cfg.AuthFailureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if remaining := time.Until(b.openUntil); remaining > 0 {
+		return fmt.Errorf("%w: %d consecutive responses were 401 or 403; retry in %v",
+			ErrCircuitOpen, b.failures, remaining.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// record updates the breaker with the response to a request. Authentication failures count towards opening the
+// breaker and successful or redirected responses close it. Other failures, and requests that failed without a
+// response, say nothing about the token and are ignored.
+func (b *authBreaker) record(cfg *Configuration, resp *http.Response) {
+	if cfg.AuthFailureThreshold <= 0 || resp == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 400:
+		b.failures, b.openUntil = 0, time.Time{}
+		return
+	case resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden:
+		return
+	}
+
+	b.failures++
+	if b.failures >= cfg.AuthFailureThreshold {
+		cooldown := cfg.AuthFailureCooldown
+		if cooldown <= 0 {
+			cooldown = defaultAuthFailureCooldown
+		}
+		b.openUntil = time.Now().Add(cooldown)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AuthBreaker(t *testing.T) {
+	const cooldown = 100 * time.Millisecond
+	var requests int32
+	var status int32 = http.StatusUnauthorized
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		code := int(atomic.LoadInt32(&status))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		if code == http.StatusOK {
+			_, _ = w.Write([]byte(`{"id": "1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"code": %d, "message": %q}`, code, http.StatusText(code))))
+	}, func(cfg *Configuration) {
+		cfg.AuthFailureThreshold = 2
+		cfg.AuthFailureCooldown = cooldown
+	})
+	auth := NewAuthContext("pul-123")
+
+	for i := 0; i < 2; i++ {
+		_, err := apiClient.OpenEnvironment(auth, "test-org", "test-env")
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+
+	_, err := apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// After the cooldown a single request is let through; failing again reopens the breaker immediately.
+	time.Sleep(cooldown)
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.NotErrorIs(t, err, ErrCircuitOpen)
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// Other failures leave the breaker as it was, so the next authentication failure reopens it immediately.
+	time.Sleep(cooldown)
+	atomic.StoreInt32(&status, http.StatusInternalServerError)
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.NotErrorIs(t, err, ErrCircuitOpen)
+	atomic.StoreInt32(&status, http.StatusNotFound)
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.ErrorIs(t, err, ErrEnvironmentNotFound)
+	atomic.StoreInt32(&status, http.StatusForbidden)
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.NotErrorIs(t, err, ErrCircuitOpen)
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, int32(6), atomic.LoadInt32(&requests))
+
+	// A successful response closes the breaker.
+	time.Sleep(cooldown)
+	atomic.StoreInt32(&status, http.StatusOK)
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	atomic.StoreInt32(&status, http.StatusForbidden)
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.NotErrorIs(t, err, ErrCircuitOpen)
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.NotErrorIs(t, err, ErrCircuitOpen)
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, int32(9), atomic.LoadInt32(&requests))
+}
+
+func Test_AuthBreakerDisabled(t *testing.T) {
+	var breaker authBreaker
+	cfg := NewConfiguration()
+	for i := 0; i < 10; i++ {
+		require.Nil(t, breaker.allow(cfg))
+		breaker.record(cfg, &http.Response{StatusCode: http.StatusUnauthorized})
+	}
+}
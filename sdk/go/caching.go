@@ -0,0 +1,232 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"gopkg.in/ghodss/yaml.v1"
+)
+
+// CacheOptions configures the environment cache used by a CachingClient.
+type CacheOptions struct {
+	// MaxEntries bounds the number of cached environment definitions. When the cache is full, the least recently
+	// stored entry is evicted. Zero means unbounded.
+	MaxEntries int
+}
+
+// CachingClient is an EscClient that caches environment definitions read with GetEnvironment.
+// Cached definitions are revalidated with the service using their ETag on every read, so a cached
+// definition is only returned if the environment has not changed since it was stored.
+// Updates and deletes made through the CachingClient invalidate the affected entry.
+//
+// Every method that reads the current definition of an environment goes through the cache: GetEnvironment,
+// GetEnvironmentRaw, GetEnvironmentJSON, GetEnvironmentYamlCanonical, GetEnvironmentScoped, CheckDrift,
+// GetEffectiveEnvironmentDefinition and the import graph methods. The other methods of the embedded EscClient,
+// including GetEnvironmentAtVersion, DecryptEnvironment and those that open environments, always call the service.
+type CachingClient struct {
+	*EscClient
+
+	opts    CacheOptions
+	mu      sync.Mutex
+	seq     uint64
+	entries map[string]*cachedEnvironment
+}
+
+type cachedEnvironment struct {
+	yaml string
+	etag string
+	seq  uint64
+}
+
+// NewCachingClient creates a new ESC client with the given configuration that caches environment definitions.
+func NewCachingClient(cfg *Configuration, opts CacheOptions) *CachingClient {
+	return &CachingClient{
+		EscClient: NewClient(cfg),
+		opts:      opts,
+		entries:   make(map[string]*cachedEnvironment),
+	}
+}
+
+func environmentCacheKey(org, envName string) string {
+	return org + "/" + envName
+}
+
+// GetEnvironment retrieves the environment with the given name in the given organization, returning the cached
+// definition if the service reports that it is still current.
+func (c *CachingClient) GetEnvironment(ctx context.Context, org, envName string) (*EnvironmentDefinition, string, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, "", err
+	}
+
+	key := environmentCacheKey(org, envName)
+
+	c.mu.Lock()
+	cached := c.entries[key]
+	c.mu.Unlock()
+
+	path, err := c.environmentURL(ctx, "GetEnvironment", org, envName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	headers := map[string]string{"Accept": "application/x-yaml"}
+	if cached != nil && cached.etag != "" {
+		headers["If-None-Match"] = cached.etag
+	}
+
+	resp, body, err := c.rawRequest(ctx, "GetEnvironment", http.MethodGet, path, headers, nil)
+	if resp != nil && resp.StatusCode == http.StatusNotModified && cached != nil {
+		// Decode the cached YAML afresh so that callers cannot modify the cached definition.
+		body, err = []byte(cached.yaml), nil
+	}
+	if err != nil {
+		c.invalidate(org, envName)
+		return nil, "", wrapNotFound(err, resp, org, envName)
+	}
+
+	var env EnvironmentDefinition
+	if err := yaml.Unmarshal(body, &env); err != nil {
+		return nil, "", err
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" && resp.StatusCode != http.StatusNotModified {
+		c.store(key, &cachedEnvironment{yaml: string(body), etag: etag})
+	}
+	return &env, string(body), nil
+}
+
+// GetEnvironmentRaw is like EscClient.GetEnvironmentRaw, but reads the definition through the cache.
+func (c *CachingClient) GetEnvironmentRaw(ctx context.Context, org, envName string) (string, error) {
+	_, yaml, err := c.GetEnvironment(ctx, org, envName)
+	return yaml, err
+}
+
+// GetEnvironmentJSON is like EscClient.GetEnvironmentJSON, but reads the definition through the cache and converts
+// it to JSON.
+func (c *CachingClient) GetEnvironmentJSON(ctx context.Context, org, envName string) (*EnvironmentDefinition, []byte, error) {
+	env, doc, err := c.GetEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := yaml.YAMLToJSON([]byte(doc))
+	if err != nil {
+		return nil, nil, err
+	}
+	return env, body, nil
+}
+
+// GetEnvironmentYamlCanonical is like EscClient.GetEnvironmentYamlCanonical, but reads the definition through the cache.
+func (c *CachingClient) GetEnvironmentYamlCanonical(ctx context.Context, org, envName string) (string, error) {
+	return environmentYamlCanonical(ctx, org, envName, c.GetEnvironment)
+}
+
+// GetEnvironmentScoped is like EscClient.GetEnvironmentScoped, but reads the definition through the cache.
+func (c *CachingClient) GetEnvironmentScoped(ctx context.Context) (*EnvironmentDefinition, string, error) {
+	scope, err := requireEnvironmentScope(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return c.GetEnvironment(ctx, scope.Org, scope.EnvName)
+}
+
+// CheckDrift is like EscClient.CheckDrift, but reads the service's definition through the cache.
+func (c *CachingClient) CheckDrift(ctx context.Context, org, envName, localYaml string) (*DriftResult, error) {
+	return checkDrift(ctx, org, envName, localYaml, c.GetEnvironment)
+}
+
+// GetEffectiveEnvironmentDefinition is like EscClient.GetEffectiveEnvironmentDefinition, but reads definitions
+// through the cache.
+func (c *CachingClient) GetEffectiveEnvironmentDefinition(ctx context.Context, org, envName string) (*EnvironmentDefinition, error) {
+	return effectiveEnvironmentDefinition(ctx, org, envName, c.GetEnvironment)
+}
+
+// GetEnvironmentImports is like EscClient.GetEnvironmentImports, but reads the definition through the cache.
+func (c *CachingClient) GetEnvironmentImports(ctx context.Context, org, envName string) ([]string, error) {
+	env, _, err := c.GetEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.Imports, nil
+}
+
+// GetEnvironmentImportGraph is like EscClient.GetEnvironmentImportGraph, but reads definitions through the cache.
+func (c *CachingClient) GetEnvironmentImportGraph(ctx context.Context, org string) (map[string][]string, error) {
+	return c.environmentImportGraph(ctx, org, c.GetEnvironmentImports)
+}
+
+// GetEnvironmentImporters is like EscClient.GetEnvironmentImporters, but reads definitions through the cache.
+func (c *CachingClient) GetEnvironmentImporters(ctx context.Context, org, envName string) ([]EnvironmentRef, error) {
+	graph, err := c.GetEnvironmentImportGraph(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	return importers(graph, envName), nil
+}
+
+// UpdateEnvironmentYaml is like EscClient.UpdateEnvironmentYaml, but also invalidates the cached definition.
+func (c *CachingClient) UpdateEnvironmentYaml(ctx context.Context, org, envName, yaml string) (*EnvironmentDiagnostics, error) {
+	defer c.invalidate(org, envName)
+	return c.EscClient.UpdateEnvironmentYaml(ctx, org, envName, yaml)
+}
+
+// UpdateEnvironment is like EscClient.UpdateEnvironment, but also invalidates the cached definition.
+func (c *CachingClient) UpdateEnvironment(ctx context.Context, org, envName string, env *EnvironmentDefinition) (*EnvironmentDiagnostics, error) {
+	defer c.invalidate(org, envName)
+	return c.EscClient.UpdateEnvironment(ctx, org, envName, env)
+}
+
+// SetEnvironmentValue is like EscClient.SetEnvironmentValue, but also invalidates the cached definition.
+func (c *CachingClient) SetEnvironmentValue(ctx context.Context, org, envName, path string, value any) (*EnvironmentDiagnostics, error) {
+	defer c.invalidate(org, envName)
+	return c.EscClient.SetEnvironmentValue(ctx, org, envName, path, value)
+}
+
+// UnsetEnvironmentValue is like EscClient.UnsetEnvironmentValue, but also invalidates the cached definition.
+func (c *CachingClient) UnsetEnvironmentValue(ctx context.Context, org, envName, path string) (*EnvironmentDiagnostics, error) {
+	defer c.invalidate(org, envName)
+	return c.EscClient.UnsetEnvironmentValue(ctx, org, envName, path)
+}
+
+// UnsetEnvironmentValueAndPrune is like EscClient.UnsetEnvironmentValueAndPrune, but also invalidates the cached definition.
+func (c *CachingClient) UnsetEnvironmentValueAndPrune(ctx context.Context, org, envName, path string) (*EnvironmentDiagnostics, error) {
+	defer c.invalidate(org, envName)
+	return c.EscClient.UnsetEnvironmentValueAndPrune(ctx, org, envName, path)
+}
+
+// DeleteEnvironment is like EscClient.DeleteEnvironment, but also invalidates the cached definition.
+func (c *CachingClient) DeleteEnvironment(ctx context.Context, org, envName string) error {
+	defer c.invalidate(org, envName)
+	return c.EscClient.DeleteEnvironment(ctx, org, envName)
+}
+
+func (c *CachingClient) store(key string, entry *cachedEnvironment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+	entry.seq = c.seq
+	c.entries[key] = entry
+
+	if c.opts.MaxEntries > 0 && len(c.entries) > c.opts.MaxEntries {
+		var oldestKey string
+		var oldestSeq uint64
+		for k, e := range c.entries {
+			if oldestKey == "" || e.seq < oldestSeq {
+				oldestKey, oldestSeq = k, e.seq
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+}
+
+func (c *CachingClient) invalidate(org, envName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, environmentCacheKey(org, envName))
+}
@@ -0,0 +1,77 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CachingClient(t *testing.T) {
+	var requests, notModified int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			requests++
+			if r.Header.Get("If-None-Match") == `"1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"1"`)
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = w.Write([]byte("values:\n  foo: bar\n"))
+		case http.MethodPatch:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	apiClient := NewCachingClient(client.rawClient.cfg, CacheOptions{})
+	auth := NewAuthContext("pul-123")
+
+	for i := 0; i < 2; i++ {
+		env, yaml, err := apiClient.GetEnvironment(auth, "test-org", "test-env")
+		require.Nil(t, err)
+		require.Equal(t, "bar", env.Values.AdditionalProperties["foo"])
+		require.Equal(t, "values:\n  foo: bar\n", yaml)
+	}
+	require.Equal(t, 2, requests)
+	require.Equal(t, 1, notModified)
+
+	_, err := apiClient.UpdateEnvironmentYaml(auth, "test-org", "test-env", "values:\n  foo: baz\n")
+	require.Nil(t, err)
+
+	_, _, err = apiClient.GetEnvironment(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, 3, requests)
+	require.Equal(t, 1, notModified)
+
+	// Every method that reads the current definition revalidates the cached entry rather than reading it afresh.
+	raw, err := apiClient.GetEnvironmentRaw(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "values:\n  foo: bar\n", raw)
+
+	_, body, err := apiClient.GetEnvironmentJSON(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.JSONEq(t, `{"values": {"foo": "bar"}}`, string(body))
+
+	canonical, err := apiClient.GetEnvironmentYamlCanonical(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "values:\n  foo: bar\n", canonical)
+
+	_, _, err = apiClient.GetEnvironmentScoped(WithEnvironmentScope(auth, "test-org", "test-env"))
+	require.Nil(t, err)
+
+	drift, err := apiClient.CheckDrift(auth, "test-org", "test-env", "values: {foo: bar}")
+	require.Nil(t, err)
+	require.False(t, drift.Drifted)
+
+	effective, err := apiClient.GetEffectiveEnvironmentDefinition(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "bar", effective.Values.AdditionalProperties["foo"])
+
+	require.Equal(t, 9, requests)
+	require.Equal(t, 7, notModified)
+}
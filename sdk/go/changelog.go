@@ -0,0 +1,112 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ChangelogEntry summarizes a revision of an environment.
+type ChangelogEntry struct {
+	Revision     int32
+	Created      time.Time
+	CreatorLogin string
+	CreatorName  string
+	// Tags are the names of the tags that currently point at the revision, sorted.
+	Tags []string
+}
+
+// GetEnvironmentChangelog returns a summary of the revisions of the environment with the given name in the given
+// organization, newest first, along with the tags that point at each revision. If limit is positive, only the most
+// recent limit revisions are returned.
+func (c *EscClient) GetEnvironmentChangelog(ctx context.Context, org, envName string, limit int) ([]ChangelogEntry, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
+	var revs []EnvironmentRevision
+	var err error
+	if limit > 0 {
+		revs, _, err = c.EscAPI.ListEnvironmentRevisions(ctx, org, envName).Count(int32(limit)).Execute()
+	} else {
+		revs, err = c.ListAllEnvironmentRevisions(ctx, org, envName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := c.listAllEnvironmentRevisionTags(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+	tagsByRevision := make(map[int32][]string)
+	for _, tag := range tags {
+		tagsByRevision[tag.Revision] = append(tagsByRevision[tag.Revision], tag.Name)
+	}
+
+	entries := make([]ChangelogEntry, 0, len(revs))
+	for _, rev := range revs {
+		created, err := parseRevisionTime(rev.GetCreated())
+		if err != nil {
+			return nil, fmt.Errorf("revision %d: %w", rev.Number, err)
+		}
+
+		revTags := tagsByRevision[rev.Number]
+		sort.Strings(revTags)
+		entries = append(entries, ChangelogEntry{
+			Revision:     rev.Number,
+			Created:      created,
+			CreatorLogin: rev.GetCreatorLogin(),
+			CreatorName:  rev.GetCreatorName(),
+			Tags:         revTags,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Revision > entries[j].Revision })
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// GetRevisionTagMap returns the revision number that each tag of the environment with the given name in the given
+// organization currently points at, keyed by tag name.
+func (c *EscClient) GetRevisionTagMap(ctx context.Context, org, envName string) (map[string]int32, error) {
+	tags, err := c.listAllEnvironmentRevisionTags(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make(map[string]int32, len(tags))
+	for _, tag := range tags {
+		revisions[tag.Name] = tag.Revision
+	}
+	return revisions, nil
+}
+
+// listAllEnvironmentRevisionTags lists every tag of the environment with the given name in the given organization,
+// following continuation tokens until every page has been read.
+func (c *EscClient) listAllEnvironmentRevisionTags(ctx context.Context, org, envName string) ([]EnvironmentRevisionTag, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
+	var all []EnvironmentRevisionTag
+	request := c.EscAPI.ListEnvironmentRevisionTags(ctx, org, envName)
+	for {
+		page, _, err := request.Execute()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Tags...)
+
+		next := page.GetNextToken()
+		if len(page.Tags) == 0 || next == "" {
+			return all, nil
+		}
+		request = c.EscAPI.ListEnvironmentRevisionTags(ctx, org, envName).After(next)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestHistoryServer serves the revisions and tags of a single environment with the given number of revisions.
+// Revisions are listed newest first, two per page, and tags one per page, so that callers must follow pagination.
+// The requests received are recorded in order.
+func newTestHistoryServer(t *testing.T, latest int32, tags map[string]int32) (*EscClient, *[]string) {
+	var requests []string
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		query := r.URL.Query()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/environments/test-org/test-env/versions":
+			newest, count := latest, 2
+			if before := query.Get("before"); before != "" {
+				n, err := strconv.Atoi(before)
+				require.Nil(t, err)
+				newest = int32(n) - 1
+			}
+			if c := query.Get("count"); c != "" {
+				n, err := strconv.Atoi(c)
+				require.Nil(t, err)
+				count = n
+			}
+
+			revs := []EnvironmentRevision{}
+			for n := newest; n > 0 && len(revs) < count; n-- {
+				created := time.Date(2024, 1, int(n), 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+				login := fmt.Sprintf("user-%d", n)
+				revs = append(revs, EnvironmentRevision{Number: n, Created: &created, CreatorLogin: &login})
+			}
+			_ = json.NewEncoder(w).Encode(revs)
+		case "/environments/test-org/test-env/versions/tags":
+			names := make([]string, 0, len(tags))
+			for name := range tags {
+				if name > query.Get("after") {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+
+			var page EnvironmentRevisionTags
+			if len(names) > 0 {
+				page.Tags = []EnvironmentRevisionTag{{Name: names[0], Revision: tags[names[0]]}}
+			}
+			if len(names) > 1 {
+				page.NextToken = &names[0]
+			}
+			_ = json.NewEncoder(w).Encode(page)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 404, "message": "not found"}`))
+		}
+	}), &requests
+}
+
+func Test_GetEnvironmentChangelog(t *testing.T) {
+	apiClient, requests := newTestHistoryServer(t, 5, map[string]int32{"latest": 5, "stable": 3, "approved": 3})
+	auth := NewAuthContext("pul-123")
+
+	entries, err := apiClient.GetEnvironmentChangelog(auth, "test-org", "test-env", 0)
+	require.Nil(t, err)
+	require.Len(t, entries, 5)
+	for i, entry := range entries {
+		require.Equal(t, int32(5-i), entry.Revision)
+		require.Equal(t, time.Date(2024, 1, 5-i, 0, 0, 0, 0, time.UTC), entry.Created)
+		require.Equal(t, fmt.Sprintf("user-%d", 5-i), entry.CreatorLogin)
+	}
+	require.Equal(t, []string{"latest"}, entries[0].Tags)
+	require.Equal(t, []string{"approved", "stable"}, entries[2].Tags)
+	require.Empty(t, entries[1].Tags)
+	require.Equal(t, []string{
+		"GET /environments/test-org/test-env/versions",
+		"GET /environments/test-org/test-env/versions?before=4",
+		"GET /environments/test-org/test-env/versions?before=2",
+		"GET /environments/test-org/test-env/versions/tags",
+		"GET /environments/test-org/test-env/versions/tags?after=approved",
+		"GET /environments/test-org/test-env/versions/tags?after=latest",
+	}, *requests)
+
+	*requests = nil
+	entries, err = apiClient.GetEnvironmentChangelog(auth, "test-org", "test-env", 3)
+	require.Nil(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, int32(5), entries[0].Revision)
+	require.Equal(t, int32(3), entries[2].Revision)
+	require.Equal(t, "GET /environments/test-org/test-env/versions?count=3", (*requests)[0])
+
+	_, err = apiClient.GetEnvironmentChangelog(auth, "test-org", "missing", 0)
+	require.Error(t, err)
+}
+
+func Test_GetRevisionTagMap(t *testing.T) {
+	apiClient, requests := newTestHistoryServer(t, 5, map[string]int32{"latest": 5, "stable": 3, "approved": 3})
+	auth := NewAuthContext("pul-123")
+
+	tags, err := apiClient.GetRevisionTagMap(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, map[string]int32{"latest": 5, "stable": 3, "approved": 3}, tags)
+	require.Equal(t, []string{
+		"GET /environments/test-org/test-env/versions/tags",
+		"GET /environments/test-org/test-env/versions/tags?after=approved",
+		"GET /environments/test-org/test-env/versions/tags?after=latest",
+	}, *requests)
+
+	apiClient, _ = newTestHistoryServer(t, 1, nil)
+	tags, err = apiClient.GetRevisionTagMap(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Empty(t, tags)
+
+	_, err = apiClient.GetRevisionTagMap(auth, "test-org", "missing")
+	require.Error(t, err)
+}
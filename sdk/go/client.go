@@ -50,6 +50,9 @@ type RawAPIClient struct {
 	cfg    *Configuration
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
+	// authBreaker suspends requests after repeated authentication failures.
+	authBreaker authBreaker
+
 	// API Services
 
 	EscAPI *EscAPIService
@@ -240,7 +243,23 @@ func parameterToJson(obj interface{}) (string, error) {
 }
 
 // callAPI do the request.
-func (c *RawAPIClient) callAPI(request *http.Request) (*http.Response, error) {
+func (c *RawAPIClient) callAPI(request *http.Request, operation string) (*http.Response, error) {
+	if c.cfg.Tracer != nil {
+		return c.callAPIWithSpan(request, operation)
+	}
+	return c.doRequest(request)
+}
+
+// doRequest sends the request, dumping it and its response when debugging is enabled.
+func (c *RawAPIClient) doRequest(request *http.Request) (*http.Response, error) {
+	if err := c.authBreaker.allow(c.cfg); err != nil {
+		return nil, err
+	}
+
+	if err := c.authorizeRequest(request); err != nil {
+		return nil, err
+	}
+
 	if c.cfg.Debug {
 		dump, err := httputil.DumpRequestOut(request, true)
 		if err != nil {
@@ -249,11 +268,24 @@ func (c *RawAPIClient) callAPI(request *http.Request) (*http.Response, error) {
 		log.Printf("\n%s\n", string(dump))
 	}
 
-	resp, err := c.cfg.HTTPClient.Do(request)
+	if c.cfg.EnableCompression {
+		if err := compressRequest(request); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.sendWithRefresh(request)
+	c.authBreaker.record(c.cfg, resp)
 	if err != nil {
 		return resp, err
 	}
 
+	if c.cfg.EnableCompression {
+		if err := decompressResponse(resp); err != nil {
+			return resp, err
+		}
+	}
+
 	if c.cfg.Debug {
 		dump, err := httputil.DumpResponse(resp, true)
 		if err != nil {
@@ -407,6 +439,11 @@ func (c *RawAPIClient) prepareRequest(
 	// Add the user agent to the request.
 	localVarRequest.Header.Add("User-Agent", c.cfg.UserAgent)
 
+	// Ask for diagnostics and error messages in the configured language, if any.
+	if c.cfg.Language != "" {
+		localVarRequest.Header.Set("Accept-Language", c.cfg.Language)
+	}
+
 	if ctx != nil {
 		// add context to the request
 		localVarRequest = localVarRequest.WithContext(ctx)
@@ -418,6 +455,11 @@ func (c *RawAPIClient) prepareRequest(
 	for header, value := range c.cfg.DefaultHeader {
 		localVarRequest.Header.Add(header, value)
 	}
+
+	// Add any per-call headers attached to the context.
+	if ctx != nil {
+		setContextHeaders(ctx, localVarRequest.Header)
+	}
 	return localVarRequest, nil
 }
 
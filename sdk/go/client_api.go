@@ -0,0 +1,26 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import "context"
+
+// EscClientAPI is the interface implemented by EscClient for the environment operations that most applications use.
+// Code that depends on EscClientAPI rather than on *EscClient can be tested with a fake such as the in-memory
+// implementation in the fakes package.
+type EscClientAPI interface {
+	ListEnvironments(ctx context.Context, org string, continuationToken *string) (*OrgEnvironments, error)
+	GetEnvironment(ctx context.Context, org, envName string) (*EnvironmentDefinition, string, error)
+	CreateEnvironment(ctx context.Context, org, envName string) error
+	UpdateEnvironmentYaml(ctx context.Context, org, envName, yaml string) (*EnvironmentDiagnostics, error)
+	UpdateEnvironment(ctx context.Context, org, envName string, env *EnvironmentDefinition) (*EnvironmentDiagnostics, error)
+	DeleteEnvironment(ctx context.Context, org, envName string) error
+	OpenEnvironment(ctx context.Context, org, envName string) (*OpenEnvironment, error)
+	ReadOpenEnvironment(ctx context.Context, org, envName, openEnvID string) (*Environment, map[string]any, error)
+	OpenAndReadEnvironment(ctx context.Context, org, envName string) (*Environment, map[string]any, error)
+	ReadEnvironmentProperty(ctx context.Context, org, envName, openEnvID, propPath string) (*Value, any, error)
+}
+
+var (
+	_ EscClientAPI = (*EscClient)(nil)
+	_ EscClientAPI = (*CachingClient)(nil)
+)
@@ -0,0 +1,106 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// EnvironmentDefinitionsEqual reports whether two environment definitions are semantically equal, ignoring
+// formatting and key order, along with the sorted property paths at which they differ. Imports are compared
+// in order, since import order determines how values are merged. A missing object or array is considered
+// equal to an empty one.
+func EnvironmentDefinitionsEqual(a, b *EnvironmentDefinition) (bool, []string) {
+	na, errA := normalizeDefinition(a)
+	nb, errB := normalizeDefinition(b)
+	if errA != nil || errB != nil {
+		if reflect.DeepEqual(a, b) {
+			return true, nil
+		}
+		return false, []string{""}
+	}
+
+	var diffs []string
+	diffNormalized("", na, nb, &diffs)
+	sort.Strings(diffs)
+	return len(diffs) == 0, diffs
+}
+
+// normalizeDefinition converts a definition into plain JSON values so that it can be compared structurally.
+func normalizeDefinition(def *EnvironmentDefinition) (any, error) {
+	if def == nil {
+		return map[string]any{}, nil
+	}
+	bs, err := json.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func diffNormalized(path string, a, b any, diffs *[]string) {
+	if isEmptyContainer(a) && b == nil || a == nil && isEmptyContainer(b) {
+		return
+	}
+
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok {
+			*diffs = append(*diffs, path)
+			return
+		}
+		for k, av := range a {
+			diffNormalized(appendPropertyKey(path, k), av, b[k], diffs)
+		}
+		for k, bv := range b {
+			if _, ok := a[k]; !ok {
+				diffNormalized(appendPropertyKey(path, k), nil, bv, diffs)
+			}
+		}
+	case []any:
+		b, ok := b.([]any)
+		if !ok {
+			*diffs = append(*diffs, path)
+			return
+		}
+		for i := 0; i < len(a) || i < len(b); i++ {
+			if i >= len(a) || i >= len(b) {
+				*diffs = append(*diffs, appendPropertyIndex(path, i))
+				continue
+			}
+			diffNormalized(appendPropertyIndex(path, i), a[i], b[i], diffs)
+		}
+	default:
+		if !reflect.DeepEqual(a, b) {
+			*diffs = append(*diffs, path)
+		}
+	}
+}
+
+// simplePropertyKey matches keys that can be written in a property path without quoting.
+var simplePropertyKey = regexp.MustCompile(`^[^.\[\]"]+$`)
+
+// appendPropertyKey appends an object key to a property path, quoting it if necessary.
+func appendPropertyKey(path, key string) string {
+	if !simplePropertyKey.MatchString(key) {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// appendPropertyIndex appends an array index to a property path.
+func appendPropertyIndex(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
@@ -0,0 +1,46 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvironmentDefinitionsEqual(t *testing.T) {
+	a := &EnvironmentDefinition{
+		Imports: []string{"base"},
+		Values: &EnvironmentDefinitionValues{
+			PulumiConfig: map[string]any{"foo": "bar", "list": []any{1, 2}},
+			AdditionalProperties: map[string]any{
+				"x": map[string]any{"a": 1, "b": 2},
+			},
+		},
+	}
+	b := &EnvironmentDefinition{
+		Imports: []string{"base"},
+		Values: &EnvironmentDefinitionValues{
+			PulumiConfig: map[string]any{"list": []any{1.0, 2.0}, "foo": "bar"},
+			AdditionalProperties: map[string]any{
+				"x": map[string]any{"b": 2, "a": 1},
+			},
+		},
+	}
+
+	equal, diffs := EnvironmentDefinitionsEqual(a, b)
+	require.True(t, equal)
+	require.Empty(t, diffs)
+
+	b.Values.PulumiConfig["list"] = []any{1}
+	b.Values.AdditionalProperties["x"] = map[string]any{"a": 2, "b": 2}
+	b.Values.AdditionalProperties["weird.key"] = true
+
+	equal, diffs = EnvironmentDefinitionsEqual(a, b)
+	require.False(t, equal)
+	require.Equal(t, []string{
+		`values.pulumiConfig.list[1]`,
+		`values.x.a`,
+		`values["weird.key"]`,
+	}, diffs)
+}
@@ -0,0 +1,73 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+)
+
+// compressionThreshold is the minimum size, in bytes, of a YAML request body before it is gzipped.
+const compressionThreshold = 1024
+
+// compressRequest asks for a gzipped response and gzips large YAML request bodies.
+// Setting Accept-Encoding explicitly disables the transport's transparent decompression,
+// so responses must be passed through decompressResponse.
+func compressRequest(request *http.Request) error {
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	if request.Body == nil || request.ContentLength < compressionThreshold ||
+		!YamlCheck.MatchString(request.Header.Get("Content-Type")) {
+		return nil
+	}
+
+	body, err := io.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	compressed := buf.Bytes()
+	request.Body = io.NopCloser(bytes.NewReader(compressed))
+	request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	request.ContentLength = int64(len(compressed))
+	request.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// decompressResponse replaces a gzipped response body with its decompressed contents.
+func decompressResponse(resp *http.Response) error {
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		return nil
+	}
+
+	r, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(r)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewBuffer(body))
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = int64(len(body))
+	resp.Uncompressed = true
+	return nil
+}
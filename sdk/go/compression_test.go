@@ -0,0 +1,80 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type compressedRequest struct {
+	contentEncoding string
+	body            string
+}
+
+func newTestCompressionServer(t *testing.T, failures int) (*EscClient, *[]compressedRequest) {
+	var requests []compressedRequest
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("invalid gzip body: %v", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body = gz
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			t.Errorf("reading body: %v", err)
+		}
+		requests = append(requests, compressedRequest{contentEncoding: r.Header.Get("Content-Encoding"), body: string(data)})
+
+		if len(requests) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}, func(cfg *Configuration) {
+		cfg.EnableCompression = true
+		cfg.MaxRetries = failures
+	}), &requests
+}
+
+func Test_CompressRequest(t *testing.T) {
+	auth := NewAuthContext("pul-123")
+	large := "values:\n" + strings.Repeat("  key: value\n", 200)
+
+	apiClient, requests := newTestCompressionServer(t, 0)
+	_, err := apiClient.UpdateEnvironmentYaml(auth, "test-org", "test-env", large)
+	require.Nil(t, err)
+	require.Equal(t, []compressedRequest{{contentEncoding: "gzip", body: large}}, *requests)
+
+	small := "values:\n  key: value\n"
+	*requests = nil
+	_, err = apiClient.UpdateEnvironmentYaml(auth, "test-org", "test-env", small)
+	require.Nil(t, err)
+	require.Equal(t, []compressedRequest{{body: small}}, *requests)
+}
+
+func Test_CompressRequestRetry(t *testing.T) {
+	defer func(delay time.Duration) { retryBaseDelay = delay }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+
+	large := "values:\n" + strings.Repeat("  key: value\n", 200)
+	apiClient, requests := newTestCompressionServer(t, 2)
+	_, err := apiClient.UpdateEnvironmentYaml(NewAuthContext("pul-123"), "test-org", "test-env", large)
+	require.Nil(t, err)
+	require.Len(t, *requests, 3)
+	for _, r := range *requests {
+		require.Equal(t, compressedRequest{contentEncoding: "gzip", body: large}, r)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"sync"
+)
+
+// RunBounded calls fn for each of the given items, running at most concurrency calls at once.
+// The context passed to fn is cancelled as soon as any call returns an error, and no further calls are started;
+// the first error is returned once all running calls have finished. If ctx is done before every item has been
+// started, the remaining items are skipped and ctx's error is returned; once every item has been processed
+// successfully, nil is returned even if ctx was done meanwhile.
+func RunBounded[T any](ctx context.Context, items []T, concurrency int, fn func(context.Context, T) error) error {
+	return runBounded(ctx, items, concurrency, fn)
+}
+
+// runBounded implements RunBounded. It is used by the client methods that operate on many environments at once.
+func runBounded[T any](ctx context.Context, items []T, concurrency int, fn func(context.Context, T) error) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		skipped  bool
+	)
+	sem := make(chan struct{}, concurrency)
+loop:
+	for _, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-runCtx.Done():
+			skipped = true
+			break loop
+		}
+		if runCtx.Err() != nil {
+			<-sem
+			skipped = true
+			break
+		}
+
+		wg.Add(1)
+		go func(item T) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := fn(runCtx, item); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(item)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if skipped {
+		return ctx.Err()
+	}
+	return nil
+}
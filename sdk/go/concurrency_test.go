@@ -0,0 +1,65 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RunBounded(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	var running, maxRunning, sum int64
+	err := RunBounded(context.Background(), items, 4, func(ctx context.Context, i int) error {
+		n := atomic.AddInt64(&running, 1)
+		defer atomic.AddInt64(&running, -1)
+		for {
+			m := atomic.LoadInt64(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt64(&maxRunning, m, n) {
+				break
+			}
+		}
+		atomic.AddInt64(&sum, int64(i))
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, int64(4950), sum)
+	require.LessOrEqual(t, maxRunning, int64(4))
+
+	boom := errors.New("boom")
+	var started int64
+	err = RunBounded(context.Background(), items, 1, func(ctx context.Context, i int) error {
+		atomic.AddInt64(&started, 1)
+		if i == 2 {
+			return boom
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, boom)
+	require.Equal(t, int64(3), started)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = RunBounded(ctx, items[:3], 4, func(ctx context.Context, i int) error {
+		if i == 2 {
+			cancel()
+		}
+		return nil
+	})
+	require.Nil(t, err)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = RunBounded(ctx, items, 4, func(ctx context.Context, i int) error {
+		t.Fatal("unexpected call")
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
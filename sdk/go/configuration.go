@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // contextKeys are used to identify the type of value in the context.
@@ -84,6 +85,43 @@ type Configuration struct {
 	Servers          ServerConfigurations
 	OperationServers map[string]ServerConfigurations
 	HTTPClient       *http.Client
+	// Tracer, if set, is used to start a span around each API request.
+	Tracer Tracer
+	// EnableCompression requests gzipped responses and gzips large YAML request bodies.
+	EnableCompression bool
+	// DetectImportCycles makes EscClient.UpdateEnvironment check for import cycles before updating.
+	DetectImportCycles bool
+	// ValidateNames makes EscClient check organization and environment names with ValidateEnvironmentRef
+	// before creating, reading, updating or deleting environments.
+	ValidateNames bool
+	// Language, if set, is sent as the Accept-Language header so that messages are localized where supported.
+	Language string
+	// RequestTimeout, if positive, bounds the time taken by each HTTP request, including reading its response.
+	// It defaults to the value of PULUMI_ESC_HTTP_TIMEOUT.
+	RequestTimeout time.Duration
+	// MaxRetries is the number of times a request that failed with a transient error is retried.
+	// It defaults to the value of PULUMI_ESC_MAX_RETRIES.
+	MaxRetries int
+	// SecretEncryptFunc, if set, encrypts the plaintext of each `fn::secret` value written by
+	// EscClient.SetEnvironmentValue; the stored secret is the base64-encoded result, prefixed with `esc-sdk:enc:`.
+	SecretEncryptFunc func([]byte) ([]byte, error)
+	// SecretDecryptFunc, if set, decrypts each `fn::secret` value returned by EscClient.DecryptEnvironment that
+	// carries the `esc-sdk:enc:` prefix, after base64-decoding it. Other secrets are returned as they are.
+	// It should reverse SecretEncryptFunc.
+	SecretDecryptFunc func([]byte) ([]byte, error)
+	// TokenProvider, if set, is called before each request to obtain the access token to send, overriding any
+	// token in the request's context. It is responsible for caching and refreshing tokens.
+	TokenProvider func(ctx context.Context) (string, error)
+	// RefreshOnUnauthorized makes a request that is rejected with 401 Unauthorized be retried once with a token
+	// refreshed from TokenProvider. The provider can detect the refresh with TokenRefreshRequested.
+	RefreshOnUnauthorized bool
+	// AuthFailureThreshold, if positive, is the number of consecutive 401 Unauthorized or 403 Forbidden responses
+	// after which the client stops sending requests for AuthFailureCooldown, failing them with ErrCircuitOpen
+	// instead. A successful or redirected response resets the count.
+	AuthFailureThreshold int
+	// AuthFailureCooldown is how long requests are suspended once AuthFailureThreshold is reached.
+	// It defaults to one minute.
+	AuthFailureCooldown time.Duration
 }
 
 // NewConfiguration returns a new Configuration object
@@ -101,6 +139,8 @@ func NewConfiguration() *Configuration {
 		OperationServers: map[string]ServerConfigurations{
 		},
 	}
+	// Invalid values are ignored here; NewConfigurationFromEnvironment reports them.
+	_ = configureFromEnvironment(cfg)
 	return cfg
 }
 
@@ -0,0 +1,91 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/ghodss/yaml.v1"
+)
+
+// CopyEnvironmentToOrg copies the definition of the environment srcEnvName in srcOrg to a new environment destEnvName
+// in destOrg, e.g. to promote an environment from a staging organization to a production one.
+//
+// Secrets are encrypted with a key that belongs to the source organization, so the destination cannot decrypt
+// ciphertext copied as-is. If decrypt is true, the source is decrypted by the service, which requires permission to
+// decrypt it, and its secrets are sent in plaintext to be re-encrypted under the destination organization's key.
+// If decrypt is false, the definition is copied verbatim and should not contain secrets. Either way, secrets that
+// were encrypted on the client with the configuration's SecretEncryptFunc are copied still encrypted with it, even if
+// SecretDecryptFunc is set.
+//
+// If the destination environment is created but its definition cannot be written, it is deleted again.
+func (c *EscClient) CopyEnvironmentToOrg(ctx context.Context, srcOrg, srcEnvName, destOrg, destEnvName string, decrypt bool) error {
+	var yaml string
+	var err error
+	if decrypt {
+		var body []byte
+		_, body, err = c.decryptEnvironmentYaml(ctx, srcOrg, srcEnvName)
+		yaml = string(body)
+	} else {
+		_, yaml, err = c.GetEnvironment(ctx, srcOrg, srcEnvName)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := c.CreateEnvironment(ctx, destOrg, destEnvName); err != nil {
+		return err
+	}
+
+	diags, err := c.UpdateEnvironmentYaml(ctx, destOrg, destEnvName, yaml)
+	if err == nil {
+		err = diags.AsError()
+	}
+	if err != nil {
+		if deleteErr := c.DeleteEnvironment(ctx, destOrg, destEnvName); deleteErr != nil {
+			return fmt.Errorf("%w (and deleting %s/%s failed: %v)", err, destOrg, destEnvName, deleteErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// CopyProperty copies the value at the given path within the values of the environment srcEnvName in srcOrg to the
+// same path within the values of the environment destEnvName in destOrg, e.g. to promote a single secret from
+// staging to production. Paths use the same syntax as SetEnvironmentValue. The value is copied as written in the
+// source definition, so expressions such as interpolations are copied rather than their results, and it replaces
+// whatever the destination had at the path. If nothing exists at the path in the source, ErrPathNotFound is returned.
+//
+// Within a single organization, secrets are copied as ciphertext and are never decrypted. Across organizations, the
+// ciphertext cannot be used by the destination, so the source is read with DecryptEnvironment, which requires
+// permission to decrypt it, and the secrets are sent in plaintext to be re-encrypted under the destination
+// organization's key.
+func (c *EscClient) CopyProperty(
+	ctx context.Context,
+	srcOrg, srcEnvName, destOrg, destEnvName, path string,
+) (*EnvironmentDiagnostics, error) {
+	segments, err := ParsePropertyPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+	if srcOrg == destOrg {
+		doc, _, err = c.getEnvironmentDocument(ctx, srcOrg, srcEnvName)
+	} else {
+		var decrypted string
+		if _, decrypted, err = c.DecryptEnvironment(ctx, srcOrg, srcEnvName); err == nil {
+			err = yaml.Unmarshal([]byte(decrypted), &doc)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := getPropertyPath(doc["values"], segments)
+	if err != nil {
+		return nil, fmt.Errorf("copy %q from %s/%s: %w", path, srcOrg, srcEnvName, err)
+	}
+	return c.SetEnvironmentValue(ctx, destOrg, destEnvName, path, value)
+}
@@ -0,0 +1,178 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ghodss/yaml.v1"
+)
+
+func Test_CopyProperty(t *testing.T) {
+	defs := map[string]string{
+		"/environments/staging-org/app":         "values:\n  db:\n    password:\n      fn::secret:\n        ciphertext: ZXNjeAAAAAE=\n",
+		"/environments/staging-org/app/decrypt": "values:\n  db:\n    password:\n      fn::secret: hunter2\n",
+		"/environments/staging-org/prod":        "values:\n  region: us-west-2\n",
+		"/environments/prod-org/prod":           "values:\n  region: us-west-2\n",
+	}
+	var updated map[string]any
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			body, err := io.ReadAll(r.Body)
+			require.Nil(t, err)
+			require.Nil(t, yaml.Unmarshal(body, &updated))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+
+		def, ok := defs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-yaml")
+		_, _ = w.Write([]byte(def))
+	}, func(cfg *Configuration) {
+		// Secrets that were not encrypted on the client must be copied even when a decrypt function is configured.
+		cfg.SecretDecryptFunc = reverseBytes
+	})
+	auth := NewAuthContext("pul-123")
+
+	_, err := apiClient.CopyProperty(auth, "staging-org", "app", "staging-org", "prod", "db.password")
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{
+		"region": "us-west-2",
+		"db": map[string]any{
+			"password": map[string]any{"fn::secret": map[string]any{"ciphertext": "ZXNjeAAAAAE="}},
+		},
+	}, updated["values"])
+
+	_, err = apiClient.CopyProperty(auth, "staging-org", "app", "prod-org", "prod", "db.password")
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{
+		"region": "us-west-2",
+		"db":     map[string]any{"password": map[string]any{"fn::secret": "hunter2"}},
+	}, updated["values"])
+
+	_, err = apiClient.CopyProperty(auth, "staging-org", "app", "prod-org", "prod", "db.user")
+	require.ErrorIs(t, err, ErrPathNotFound)
+	require.True(t, strings.HasPrefix(err.Error(), `copy "db.user" from staging-org/app: `))
+}
+
+func Test_CopyEnvironmentToOrgClientEncryption(t *testing.T) {
+	reverse := func(b []byte) ([]byte, error) {
+		reversed := make([]byte, len(b))
+		for i := range b {
+			reversed[len(b)-1-i] = b[i]
+		}
+		return reversed, nil
+	}
+	// The service-decrypted source still holds the client-encrypted secret: base64 of "2retnuh".
+	const source = "values:\n  password:\n    fn::secret: esc-sdk:enc:MnJldG51aA==\n"
+
+	var requests []string
+	var written string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/environments/staging-org/app/decrypt":
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = w.Write([]byte(source))
+		case r.Method == http.MethodPost:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"code": 200, "message": "created"}`))
+		case r.Method == http.MethodPatch:
+			body, err := io.ReadAll(r.Body)
+			require.Nil(t, err)
+			written = string(body)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, func(cfg *Configuration) {
+		cfg.SecretEncryptFunc = reverse
+		cfg.SecretDecryptFunc = reverse
+	})
+
+	err := apiClient.CopyEnvironmentToOrg(NewAuthContext("pul-123"), "staging-org", "app", "prod-org", "app", true)
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"GET /environments/staging-org/app/decrypt",
+		"POST /environments/prod-org/app",
+		"PATCH /environments/prod-org/app",
+	}, requests)
+	require.Equal(t, source, written)
+}
+
+func Test_CopyEnvironmentToOrg(t *testing.T) {
+	const source = "# Shared settings.\nvalues:\n  region: us-west-2\n"
+
+	var requests []string
+	var written string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/environments/staging-org/app":
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = w.Write([]byte(source))
+		case r.Method == http.MethodPost && r.URL.Path == "/environments/prod-org/existing":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"code": 409, "message": "environment already exists"}`))
+		case r.Method == http.MethodPost:
+			_, _ = w.Write([]byte(`{"code": 200, "message": "created"}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/environments/prod-org/invalid":
+			_, _ = w.Write([]byte(`{"diagnostics": [{"summary": "unknown property"}]}`))
+		case r.Method == http.MethodPatch:
+			written = string(body)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodDelete:
+			_, _ = w.Write([]byte(`{"code": 200, "message": "deleted"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 404, "message": "not found"}`))
+		}
+	})
+	auth := NewAuthContext("pul-123")
+
+	err := apiClient.CopyEnvironmentToOrg(auth, "staging-org", "app", "prod-org", "app", false)
+	require.Nil(t, err)
+	require.Equal(t, source, written)
+	require.Equal(t, []string{
+		"GET /environments/staging-org/app",
+		"POST /environments/prod-org/app",
+		"PATCH /environments/prod-org/app",
+	}, requests)
+
+	requests = nil
+	err = apiClient.CopyEnvironmentToOrg(auth, "staging-org", "app", "prod-org", "existing", false)
+	require.ErrorContains(t, err, "409")
+	require.Equal(t, []string{
+		"GET /environments/staging-org/app",
+		"POST /environments/prod-org/existing",
+	}, requests)
+
+	requests = nil
+	err = apiClient.CopyEnvironmentToOrg(auth, "staging-org", "app", "prod-org", "invalid", false)
+	require.EqualError(t, err, "unknown property")
+	require.Equal(t, []string{
+		"GET /environments/staging-org/app",
+		"POST /environments/prod-org/invalid",
+		"PATCH /environments/prod-org/invalid",
+		"DELETE /environments/prod-org/invalid",
+	}, requests)
+
+	requests = nil
+	err = apiClient.CopyEnvironmentToOrg(auth, "staging-org", "missing", "prod-org", "app", false)
+	require.ErrorIs(t, err, ErrEnvironmentNotFound)
+	require.Equal(t, []string{"GET /environments/staging-org/missing"}, requests)
+}
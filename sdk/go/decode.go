@@ -0,0 +1,51 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+// Decode converts the resolved value to plain Go values, as returned by OpenAndReadEnvironment: objects become
+// map[string]any, arrays become []any and the Value wrappers around nested values are removed, along with their
+// secret, unknown and trace metadata. Use Decode when working with the typed results of ReadOpenEnvironment or
+// ReadOpenEnvironmentProperty but native values are needed for a subtree.
+func (v *Value) Decode() any {
+	if v == nil {
+		return nil
+	}
+	return decodeResolvedValue(v.Value)
+}
+
+// decodeResolvedValue implements Value.Decode. Unlike mapValuesPrimitive, it does not modify its input and also
+// accepts values that are not wrapped in pointers.
+func decodeResolvedValue(value any) any {
+	switch v := value.(type) {
+	case *Value:
+		return v.Decode()
+	case Value:
+		return v.Decode()
+	case map[string]Value:
+		decoded := make(map[string]any, len(v))
+		for k, elem := range v {
+			decoded[k] = elem.Decode()
+		}
+		return decoded
+	case []Value:
+		decoded := make([]any, len(v))
+		for i, elem := range v {
+			decoded[i] = elem.Decode()
+		}
+		return decoded
+	case map[string]any:
+		decoded := make(map[string]any, len(v))
+		for k, elem := range v {
+			decoded[k] = decodeResolvedValue(elem)
+		}
+		return decoded
+	case []any:
+		decoded := make([]any, len(v))
+		for i, elem := range v {
+			decoded[i] = decodeResolvedValue(elem)
+		}
+		return decoded
+	default:
+		return value
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValueDecode(t *testing.T) {
+	secret := true
+	value := &Value{
+		Value: map[string]Value{
+			"name":     {Value: "app"},
+			"password": {Value: "hunter2", Secret: &secret},
+			"hosts": {Value: []any{
+				&Value{Value: "a"},
+				Value{Value: map[string]Value{"port": {Value: float64(80)}}},
+			}},
+			"ids":  {Value: []Value{{Value: float64(1)}, {Value: float64(2)}}},
+			"tags": {Value: map[string]any{"team": "core"}},
+			"none": {},
+		},
+	}
+
+	require.Equal(t, map[string]any{
+		"name":     "app",
+		"password": "hunter2",
+		"hosts":    []any{"a", map[string]any{"port": float64(80)}},
+		"ids":      []any{float64(1), float64(2)},
+		"tags":     map[string]any{"team": "core"},
+		"none":     nil,
+	}, value.Decode())
+
+	var nilValue *Value
+	require.Nil(t, nilValue.Decode())
+
+	hosts := value.Value.(map[string]Value)["hosts"].Value.([]any)
+	require.IsType(t, &Value{}, hosts[0], "Decode must not modify its receiver")
+}
@@ -0,0 +1,127 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDeleteServer serves an organization containing environments with the given names, listed two per page.
+// Deleting any environment named in failing fails with a 500. The names of the deleted environments are recorded.
+func newTestDeleteServer(t *testing.T, names []string, failing map[string]bool) (*EscClient, func() []string) {
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/environments/test-org":
+			start := 0
+			if token := r.URL.Query().Get("continuationToken"); token != "" {
+				start = sort.SearchStrings(names, token)
+			}
+			page := OrgEnvironments{Environments: []OrgEnvironment{}}
+			for i := start; i < len(names) && i < start+2; i++ {
+				page.Environments = append(page.Environments, OrgEnvironment{Name: names[i]})
+			}
+			if start+2 < len(names) {
+				page.NextToken = &names[start+2]
+			}
+			_ = json.NewEncoder(w).Encode(page)
+		case r.Method == http.MethodDelete:
+			name := path.Base(r.URL.Path)
+			if failing[name] {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"code": 500, "message": "internal error"}`))
+				return
+			}
+			mu.Lock()
+			deleted = append(deleted, name)
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"code": 200, "message": "deleted"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+	return apiClient, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		result := append([]string(nil), deleted...)
+		sort.Strings(result)
+		return result
+	}
+}
+
+func Test_DeleteEnvironmentsMatching(t *testing.T) {
+	names := []string{"app-dev", "app-prod", "test-1", "test-2", "test-3"}
+	isTest := func(env OrgEnvironment) bool { return strings.HasPrefix(env.Name, "test-") }
+	auth := NewAuthContext("pul-123")
+
+	apiClient, deleted := newTestDeleteServer(t, names, nil)
+	var confirmed []string
+	result, err := apiClient.DeleteEnvironmentsMatching(auth, "test-org", isTest, func(toDelete []OrgEnvironment) bool {
+		for _, env := range toDelete {
+			confirmed = append(confirmed, env.Name)
+		}
+		return true
+	})
+	require.Nil(t, err)
+	sort.Strings(result)
+	require.Equal(t, []string{"test-1", "test-2", "test-3"}, confirmed)
+	require.Equal(t, []string{"test-1", "test-2", "test-3"}, result)
+	require.Equal(t, []string{"test-1", "test-2", "test-3"}, deleted())
+}
+
+func Test_DeleteEnvironmentsMatchingNotConfirmed(t *testing.T) {
+	apiClient, deleted := newTestDeleteServer(t, []string{"test-1", "test-2"}, nil)
+	auth := NewAuthContext("pul-123")
+
+	result, err := apiClient.DeleteEnvironmentsMatching(auth, "test-org",
+		func(OrgEnvironment) bool { return true },
+		func([]OrgEnvironment) bool { return false })
+	require.Nil(t, err)
+	require.Empty(t, result)
+	require.Empty(t, deleted())
+
+	confirmCalled := false
+	result, err = apiClient.DeleteEnvironmentsMatching(auth, "test-org",
+		func(OrgEnvironment) bool { return false },
+		func([]OrgEnvironment) bool { confirmCalled = true; return true })
+	require.Nil(t, err)
+	require.Empty(t, result)
+	require.False(t, confirmCalled)
+}
+
+func Test_DeleteEnvironmentsMatchingError(t *testing.T) {
+	apiClient, deleted := newTestDeleteServer(t, []string{"test-1"}, map[string]bool{"test-1": true})
+	auth := NewAuthContext("pul-123")
+
+	result, err := apiClient.DeleteEnvironmentsMatching(auth, "test-org",
+		func(OrgEnvironment) bool { return true },
+		func([]OrgEnvironment) bool { return true })
+	require.ErrorContains(t, err, "500")
+	require.Empty(t, result)
+	require.Empty(t, deleted())
+}
+
+func Test_DeleteEnvironmentsMatchingRequiresCallbacks(t *testing.T) {
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+	})
+	auth := NewAuthContext("pul-123")
+
+	_, err := apiClient.DeleteEnvironmentsMatching(auth, "test-org", nil, func([]OrgEnvironment) bool { return true })
+	require.EqualError(t, err, "a match callback is required")
+
+	_, err = apiClient.DeleteEnvironmentsMatching(auth, "test-org", func(OrgEnvironment) bool { return true }, nil)
+	require.EqualError(t, err, "a confirm callback is required")
+}
@@ -0,0 +1,101 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"sort"
+)
+
+// ValueDescriptor describes the shape of a resolved value.
+type ValueDescriptor struct {
+	// Type is the JSON type of the value: "string", "number", "bool", "object", "array" or "null".
+	Type string
+	// Secret reports whether the value is secret.
+	Secret bool
+	// Unknown reports whether the value is unknown, e.g. because it is the output of a provider that was not run.
+	Unknown bool
+}
+
+// DescribeValues opens and reads the environment with the given name in the given organization and describes each of
+// its resolved leaf values, keyed by property path, e.g. `environmentVariables.PORT`. Empty objects and arrays are
+// described as leaves.
+func (c *EscClient) DescribeValues(ctx context.Context, org, envName string) (map[string]ValueDescriptor, error) {
+	env, _, err := c.OpenAndReadEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+	return describeResolvedProperties(env.GetProperties()), nil
+}
+
+func describeResolvedProperties(props map[string]Value) map[string]ValueDescriptor {
+	descriptors := make(map[string]ValueDescriptor)
+	walkResolvedProperties(props, func(path string, value any, secret, unknown bool) {
+		descriptors[path] = ValueDescriptor{Type: valueType(value), Secret: secret, Unknown: unknown}
+	})
+	return descriptors
+}
+
+// valueType returns the JSON type of a resolved leaf value.
+func valueType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64, float32, int, int32, int64:
+		return "number"
+	case map[string]any, map[string]Value:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return "unknown"
+	}
+}
+
+// ListSecretPaths opens and reads the environment with the given name in the given organization and returns the
+// property paths of its secret values, e.g. `environmentVariables.DB_PASSWORD`, sorted. A secret object or array is
+// listed once by its own path; the values nested within it are not listed separately.
+func (c *EscClient) ListSecretPaths(ctx context.Context, org, envName string) ([]string, error) {
+	env, _, err := c.OpenAndReadEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+	return secretPaths(env.GetProperties()), nil
+}
+
+func secretPaths(props map[string]Value) []string {
+	paths := []string{}
+	var walk func(path string, value any)
+	walk = func(path string, value any) {
+		switch v := value.(type) {
+		case *Value:
+			if v == nil {
+				return
+			}
+			if v.GetSecret() {
+				paths = append(paths, path)
+				return
+			}
+			walk(path, v.Value)
+		case Value:
+			walk(path, &v)
+		case map[string]Value:
+			for k, elem := range v {
+				walk(appendPropertyKey(path, k), elem)
+			}
+		case []any:
+			for i, elem := range v {
+				walk(appendPropertyIndex(path, i), elem)
+			}
+		}
+	}
+	for k, v := range props {
+		walk(appendPropertyKey("", k), v)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,56 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DescribeResolvedProperties(t *testing.T) {
+	secret, unknown := true, true
+	props := map[string]Value{
+		"environmentVariables": {Value: map[string]Value{
+			"PASSWORD": {Value: "hunter2", Secret: &secret},
+			"DEBUG":    {Value: true},
+		}},
+		"hosts":  {Value: []any{&Value{Value: "a"}, &Value{Value: 2.0}}},
+		"empty":  {Value: map[string]Value{}},
+		"output": {Value: nil, Unknown: &unknown},
+	}
+
+	require.Equal(t, map[string]ValueDescriptor{
+		"environmentVariables.PASSWORD": {Type: "string", Secret: true},
+		"environmentVariables.DEBUG":    {Type: "bool"},
+		"hosts[0]":                      {Type: "string"},
+		"hosts[1]":                      {Type: "number"},
+		"empty":                         {Type: "object"},
+		"output":                        {Type: "null", Unknown: true},
+	}, describeResolvedProperties(props))
+}
+
+func Test_SecretPaths(t *testing.T) {
+	secret := true
+	props := map[string]Value{
+		"environmentVariables": {Value: map[string]Value{
+			"PASSWORD": {Value: "hunter2", Secret: &secret},
+			"DEBUG":    {Value: true},
+		}},
+		"keys": {Value: []any{&Value{Value: "public"}, &Value{Value: "private", Secret: &secret}}},
+		"creds": {Value: map[string]Value{
+			"token": {Value: "t0k3n", Secret: &secret},
+		}, Secret: &secret},
+	}
+
+	require.Equal(t, []string{"creds", "environmentVariables.PASSWORD", "keys[1]"}, secretPaths(props))
+	require.Equal(t, []string{}, secretPaths(nil))
+}
+
+func Test_ListSecretPaths(t *testing.T) {
+	apiClient := newTestOpenEnvironmentServer(t)
+
+	paths, err := apiClient.ListSecretPaths(NewAuthContext("pul-123"), "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, []string{"password"}, paths)
+}
@@ -0,0 +1,75 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DiagnosticError is an environment diagnostic as an error.
+type DiagnosticError struct {
+	Diagnostic EnvironmentDiagnostic
+}
+
+func (e *DiagnosticError) Error() string {
+	d := e.Diagnostic
+	switch {
+	case d.Range != nil:
+		return fmt.Sprintf("%s:%d:%d: %s", d.Range.Environment, d.Range.Begin.Line, d.Range.Begin.Column, d.Summary)
+	case d.GetPath() != "":
+		return fmt.Sprintf("%s: %s", d.GetPath(), d.Summary)
+	default:
+		return d.Summary
+	}
+}
+
+// DiagnosticsError joins the errors for a set of environment diagnostics. Its message is the messages of its errors,
+// one per line.
+type DiagnosticsError struct {
+	Errors []error
+}
+
+func (e *DiagnosticsError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Is reports whether any of the errors matches target, so that errors.Is looks through each diagnostic.
+func (e *DiagnosticsError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches target, so that errors.As looks through each diagnostic.
+func (e *DiagnosticsError) As(target any) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// AsError returns nil if there are no diagnostics. Otherwise it returns a DiagnosticsError with a DiagnosticError
+// for each diagnostic, including the diagnostic's location where it is known. Every diagnostic reported by the
+// service is an error.
+func (d *EnvironmentDiagnostics) AsError() error {
+	if d == nil || len(d.Diagnostics) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(d.Diagnostics))
+	for i, diag := range d.Diagnostics {
+		errs[i] = &DiagnosticError{Diagnostic: diag}
+	}
+	return &DiagnosticsError{Errors: errs}
+}
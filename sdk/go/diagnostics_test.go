@@ -0,0 +1,36 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvironmentDiagnosticsAsError(t *testing.T) {
+	var diags *EnvironmentDiagnostics
+	require.Nil(t, diags.AsError())
+	require.Nil(t, (&EnvironmentDiagnostics{}).AsError())
+
+	path := "values.foo"
+	diags = &EnvironmentDiagnostics{Diagnostics: []EnvironmentDiagnostic{
+		{Summary: "unknown property", Range: &Range{Environment: "app", Begin: Pos{Line: 3, Column: 5}}},
+		{Summary: "expected a string", Path: &path},
+		{Summary: "syntax error"},
+	}}
+	err := diags.AsError()
+	require.EqualError(t, err, "app:3:5: unknown property\nvalues.foo: expected a string\nsyntax error")
+
+	var diagsErr *DiagnosticsError
+	require.True(t, errors.As(err, &diagsErr))
+	require.Len(t, diagsErr.Errors, 3)
+	require.Equal(t, "expected a string", diagsErr.Errors[1].(*DiagnosticError).Diagnostic.Summary)
+
+	var diagErr *DiagnosticError
+	require.True(t, errors.As(err, &diagErr))
+	require.Equal(t, "unknown property", diagErr.Diagnostic.Summary)
+	require.True(t, errors.Is(err, diagsErr.Errors[2]))
+	require.False(t, errors.Is(err, errors.New("syntax error")))
+}
@@ -0,0 +1,68 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/ghodss/yaml.v1"
+)
+
+// DriftResult is the result of comparing a local environment definition with the service's.
+type DriftResult struct {
+	// Drifted reports whether the definitions differ.
+	Drifted bool
+	// Local and Remote are the canonical YAML forms of the local and the service's definitions.
+	Local  string
+	Remote string
+	// Diff is a unified diff from Remote to Local, or empty if the definitions do not differ.
+	Diff string
+}
+
+// CheckDrift compares the given local YAML definition with the current definition of the environment with the given
+// name in the given organization. Both definitions are normalized with MarshalEnvironmentDefinitionOrdered before
+// they are compared, so differences in formatting, comments and key order are not reported as drift.
+func (c *EscClient) CheckDrift(ctx context.Context, org, envName, localYaml string) (*DriftResult, error) {
+	return checkDrift(ctx, org, envName, localYaml, c.GetEnvironment)
+}
+
+// checkDrift implements CheckDrift, reading the service's definition with getEnvironment.
+func checkDrift(ctx context.Context, org, envName, localYaml string, getEnvironment environmentReader) (*DriftResult, error) {
+	var local EnvironmentDefinition
+	if err := yaml.Unmarshal([]byte(localYaml), &local); err != nil {
+		return nil, fmt.Errorf("parsing local definition: %w", err)
+	}
+	localCanonical, err := MarshalEnvironmentDefinitionOrdered(&local)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteCanonical, err := environmentYamlCanonical(ctx, org, envName, getEnvironment)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffDefinitions(org+"/"+envName, remoteCanonical, localCanonical)
+}
+
+func diffDefinitions(name, remote, local string) (*DriftResult, error) {
+	result := &DriftResult{Drifted: remote != local, Local: local, Remote: remote}
+	if !result.Drifted {
+		return result, nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(remote),
+		B:        difflib.SplitLines(local),
+		FromFile: name + " (service)",
+		ToFile:   name + " (local)",
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Diff = diff
+	return result, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckDrift(t *testing.T) {
+	apiClient := newTestDefinitionServer(t, map[string]string{
+		"app": "imports: [base]\nvalues:\n  region: us-west-2\n  pulumiConfig:\n    b: 2\n    a: 1\n",
+	})
+	auth := NewAuthContext("pul-123")
+
+	result, err := apiClient.CheckDrift(auth, "test-org", "app", `# committed
+values:
+  pulumiConfig: {a: 1, b: 2}
+  region: us-west-2
+imports:
+  - base
+`)
+	require.Nil(t, err)
+	require.False(t, result.Drifted)
+	require.Empty(t, result.Diff)
+
+	result, err = apiClient.CheckDrift(auth, "test-org", "app", "imports: [base]\nvalues:\n  region: us-east-1\n  pulumiConfig: {a: 1, b: 2}\n")
+	require.Nil(t, err)
+	require.True(t, result.Drifted)
+	require.Contains(t, result.Diff, "--- test-org/app (service)\n+++ test-org/app (local)\n")
+	require.Contains(t, result.Diff, "-  region: us-west-2\n+  region: us-east-1\n")
+
+	_, err = apiClient.CheckDrift(auth, "test-org", "app", "values: [")
+	require.ErrorContains(t, err, "parsing local definition")
+}
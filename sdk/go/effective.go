@@ -0,0 +1,110 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// GetEffectiveEnvironmentDefinition returns the definition of the environment with the given name in the given
+// organization with the values of its transitive imports merged in, without opening it. The result has no imports.
+//
+// Values are merged as ESC merges them when opening an environment: imports are applied in order, each imported
+// environment's own imports first, and the environment's local values are applied last. When the same key is defined
+// more than once, objects are merged key by key and any other value, including arrays, replaces the earlier value.
+// Expressions such as interpolations and `fn::` calls are merged as written rather than evaluated, so they may refer
+// to values that are only defined in the merged result. An import cycle is reported with an ImportCycleError.
+func (c *EscClient) GetEffectiveEnvironmentDefinition(ctx context.Context, org, envName string) (*EnvironmentDefinition, error) {
+	return effectiveEnvironmentDefinition(ctx, org, envName, c.GetEnvironment)
+}
+
+// effectiveEnvironmentDefinition implements GetEffectiveEnvironmentDefinition, reading definitions with getEnvironment.
+func effectiveEnvironmentDefinition(ctx context.Context, org, envName string, getEnvironment environmentReader) (*EnvironmentDefinition, error) {
+	effective := map[string]map[string]any{}
+	values, err := effectiveValues(ctx, org, envName, getEnvironment, nil, effective)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, err := json.Marshal(map[string]any{"values": values})
+	if err != nil {
+		return nil, err
+	}
+	var def EnvironmentDefinition
+	if err := json.Unmarshal(bs, &def); err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+// effectiveValues returns the merged values of the environment with the given name. path holds the environments whose
+// values are being computed, in order to detect cycles, and effective memoizes the values of each environment.
+func effectiveValues(
+	ctx context.Context,
+	org, envName string,
+	getEnvironment environmentReader,
+	path []string,
+	effective map[string]map[string]any,
+) (map[string]any, error) {
+	for i, name := range path {
+		if name == envName {
+			cycle := append(append([]string{}, path[i:]...), envName)
+			return nil, &ImportCycleError{Cycle: cycle}
+		}
+	}
+	if values, ok := effective[envName]; ok {
+		return values, nil
+	}
+
+	def, _, err := getEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := map[string]any{}
+	for _, imp := range def.Imports {
+		values, err := effectiveValues(ctx, org, imp, getEnvironment, append(path, envName), effective)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeValues(merged, values).(map[string]any)
+	}
+
+	normalized, err := normalizeDefinition(def)
+	if err != nil {
+		return nil, err
+	}
+	if local, ok := normalized.(map[string]any)["values"].(map[string]any); ok {
+		merged = mergeValues(merged, local).(map[string]any)
+	}
+
+	effective[envName] = merged
+	return merged, nil
+}
+
+// mergeValues merges override into base, merging objects key by key and otherwise preferring override.
+// Neither argument is modified.
+func mergeValues(base, override any) any {
+	baseObj, ok := base.(map[string]any)
+	if !ok {
+		return override
+	}
+	overrideObj, ok := override.(map[string]any)
+	if !ok {
+		return override
+	}
+
+	merged := make(map[string]any, len(baseObj)+len(overrideObj))
+	for k, v := range baseObj {
+		merged[k] = v
+	}
+	for k, v := range overrideObj {
+		if existing, ok := merged[k]; ok {
+			merged[k] = mergeValues(existing, v)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
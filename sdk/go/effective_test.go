@@ -0,0 +1,44 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"net/http"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDefinitionServer(t *testing.T, defs map[string]string) *EscClient {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		def, ok := defs[path.Base(r.URL.Path)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-yaml")
+		_, _ = w.Write([]byte(def))
+	})
+}
+
+func Test_GetEffectiveEnvironmentDefinition(t *testing.T) {
+	apiClient := newTestDefinitionServer(t, map[string]string{
+		"base":  "values:\n  region: us-east-1\n  tags: [base]\n  pulumiConfig:\n    a: 1\n    b: 1\n",
+		"aws":   "imports: [base]\nvalues:\n  region: us-west-2\n  pulumiConfig:\n    b: 2\n",
+		"app":   "imports: [base, aws]\nvalues:\n  tags: [app]\n  pulumiConfig:\n    c: 3\n",
+		"loop":  "imports: [cycle]\n",
+		"cycle": "imports: [loop]\n",
+	})
+	auth := NewAuthContext("pul-123")
+
+	def, err := apiClient.GetEffectiveEnvironmentDefinition(auth, "test-org", "app")
+	require.Nil(t, err)
+	require.Empty(t, def.Imports)
+	require.Equal(t, map[string]any{"a": 1.0, "b": 2.0, "c": 3.0}, def.Values.PulumiConfig)
+	require.Equal(t, map[string]any{"region": "us-west-2", "tags": []any{"app"}}, def.Values.AdditionalProperties)
+
+	_, err = apiClient.GetEffectiveEnvironmentDefinition(auth, "test-org", "loop")
+	require.ErrorIs(t, err, ErrImportCycle)
+	require.Equal(t, []string{"loop", "cycle", "loop"}, err.(*ImportCycleError).Cycle)
+}
@@ -0,0 +1,192 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gopkg.in/ghodss/yaml.v1"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// SetEnvironmentValue sets the value at the given path within the values of the environment with the given name
+// in the given organization. Paths use Pulumi property path syntax, e.g. `pulumiConfig.aws:region` or `hosts[0].name`,
+// and missing intermediate objects are created. The rest of the definition is left as written, including its comments
+// and key order. The update is conditional on the environment not having been modified since it was read; if it was,
+// ErrEnvironmentModified is returned.
+// If the client's configuration sets SecretEncryptFunc, the plaintext of any `fn::secret` values within value
+// is encrypted with it before it is sent.
+func (c *EscClient) SetEnvironmentValue(ctx context.Context, org, envName, path string, value any) (*EnvironmentDiagnostics, error) {
+	segments, err := ParsePropertyPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err = c.encryptSecrets(value)
+	if err != nil {
+		return nil, err
+	}
+	var node yamlv3.Node
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+
+	doc, etag, err := c.getEnvironmentNode(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := setPropertyNode(doc, append([]any{"values"}, segments...), &node); err != nil {
+		return nil, err
+	}
+
+	return c.updateEnvironmentDocument(ctx, org, envName, doc, etag)
+}
+
+// UnsetEnvironmentValue removes the value at the given path within the values of the environment with the given name
+// in the given organization. Paths use the same syntax as SetEnvironmentValue; removing an array element shifts the
+// elements that follow it. As with SetEnvironmentValue, the rest of the definition is left as written.
+// If nothing exists at the path, ErrPathNotFound is returned.
+func (c *EscClient) UnsetEnvironmentValue(ctx context.Context, org, envName, path string) (*EnvironmentDiagnostics, error) {
+	return c.unsetEnvironmentValue(ctx, org, envName, path, false)
+}
+
+// UnsetEnvironmentValueAndPrune is like UnsetEnvironmentValue, but also removes any objects or arrays
+// that are left empty by the removal.
+func (c *EscClient) UnsetEnvironmentValueAndPrune(ctx context.Context, org, envName, path string) (*EnvironmentDiagnostics, error) {
+	return c.unsetEnvironmentValue(ctx, org, envName, path, true)
+}
+
+func (c *EscClient) unsetEnvironmentValue(ctx context.Context, org, envName, path string, prune bool) (*EnvironmentDiagnostics, error) {
+	segments, err := ParsePropertyPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, etag, err := c.getEnvironmentNode(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unsetPropertyNode(doc, append([]any{"values"}, segments...), prune); err != nil {
+		return nil, fmt.Errorf("unset %q: %w", path, err)
+	}
+
+	return c.updateEnvironmentDocument(ctx, org, envName, doc, etag)
+}
+
+// getEnvironmentDefinition reads the YAML definition of the given environment, along with the ETag identifying the
+// revision that was read.
+func (c *EscClient) getEnvironmentDefinition(ctx context.Context, org, envName string) ([]byte, string, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, "", err
+	}
+
+	_, resp, err := c.EscAPI.GetEnvironment(ctx, org, envName).Execute()
+	if err != nil {
+		return nil, "", wrapNotFound(err, resp, org, envName)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, resp.Header.Get("ETag"), nil
+}
+
+// getEnvironmentDocument reads the definition of the given environment as a generic YAML document,
+// along with the ETag identifying the revision that was read.
+func (c *EscClient) getEnvironmentDocument(ctx context.Context, org, envName string) (map[string]any, string, error) {
+	body, etag, err := c.getEnvironmentDefinition(ctx, org, envName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return nil, "", err
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return doc, etag, nil
+}
+
+// getEnvironmentNode reads the definition of the given environment as the top-level mapping node of a YAML document,
+// along with the ETag identifying the revision that was read. Unlike getEnvironmentDocument, the node retains the
+// definition's comments, key order and number types, so that it can be edited and written back without loss.
+func (c *EscClient) getEnvironmentNode(ctx context.Context, org, envName string) (*yamlv3.Node, string, error) {
+	body, etag, err := c.getEnvironmentDefinition(ctx, org, envName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(body, &doc); err != nil {
+		return nil, "", err
+	}
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 || isNullNode(doc.Content[0]) {
+		return &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}, etag, nil
+	}
+	if root := doc.Content[0]; root.Kind == yamlv3.MappingNode {
+		return root, etag, nil
+	}
+	return nil, "", fmt.Errorf("the definition of %s/%s is not an object", org, envName)
+}
+
+// updateEnvironmentDocument replaces the definition of the given environment with the given YAML document.
+// If etag is non-empty, the update only succeeds if the environment is still at the revision it identifies.
+func (c *EscClient) updateEnvironmentDocument(ctx context.Context, org, envName string, doc *yamlv3.Node, etag string) (*EnvironmentDiagnostics, error) {
+	var body bytes.Buffer
+	enc := yamlv3.NewEncoder(&body)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	path, err := c.environmentURL(ctx, "UpdateEnvironmentYaml", org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{"Content-Type": "application/x-yaml"}
+	if etag != "" {
+		headers["If-Match"] = etag
+	}
+
+	resp, respBody, err := c.rawRequest(ctx, "UpdateEnvironmentYaml", http.MethodPatch, path, headers, body.String())
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusConflict, http.StatusPreconditionFailed:
+			return nil, fmt.Errorf("%w: %v", ErrEnvironmentModified, err)
+		case http.StatusNotFound:
+			return nil, wrapNotFound(err, resp, org, envName)
+		}
+	}
+
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusBadRequest {
+			var diags EnvironmentDiagnostics
+			if json.Unmarshal(respBody, &diags) == nil {
+				return &diags, err
+			}
+		}
+		return nil, err
+	}
+
+	var diags EnvironmentDiagnostics
+	if len(respBody) != 0 {
+		if err := json.Unmarshal(respBody, &diags); err != nil {
+			return nil, err
+		}
+	}
+	return &diags, nil
+}
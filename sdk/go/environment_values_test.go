@@ -0,0 +1,126 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testCommentedDefinition = `# Shared settings.
+imports:
+  - base
+values:
+  # The service port.
+  port: 8080
+  id: 12345678901234567890
+  zone: us-west-2 # primary
+  pulumiConfig:
+    app:name: demo
+`
+
+// newTestDefinitionUpdateServer serves the given definition and records the body of each update.
+func newTestDefinitionUpdateServer(t *testing.T, definition string) (*EscClient, *[]string) {
+	var updates []string
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/x-yaml")
+			w.Header().Set("ETag", "rev-1")
+			_, _ = w.Write([]byte(definition))
+		case http.MethodPatch:
+			require.Equal(t, "rev-1", r.Header.Get("If-Match"))
+			updates = append(updates, string(body))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}), &updates
+}
+
+func Test_SetEnvironmentValuePreservesDefinition(t *testing.T) {
+	apiClient, updates := newTestDefinitionUpdateServer(t, testCommentedDefinition)
+	auth := NewAuthContext("pul-123")
+
+	_, err := apiClient.SetEnvironmentValue(auth, "test-org", "test-env", "zone", "eu-west-1")
+	require.Nil(t, err)
+	_, err = apiClient.SetEnvironmentValue(auth, "test-org", "test-env", "pulumiConfig.replicas", 3)
+	require.Nil(t, err)
+
+	require.Equal(t, []string{
+		`# Shared settings.
+imports:
+  - base
+values:
+  # The service port.
+  port: 8080
+  id: 12345678901234567890
+  zone: eu-west-1 # primary
+  pulumiConfig:
+    app:name: demo
+`,
+		`# Shared settings.
+imports:
+  - base
+values:
+  # The service port.
+  port: 8080
+  id: 12345678901234567890
+  zone: us-west-2 # primary
+  pulumiConfig:
+    app:name: demo
+    replicas: 3
+`,
+	}, *updates)
+
+	apiClient, updates = newTestDefinitionUpdateServer(t, "")
+	_, err = apiClient.SetEnvironmentValue(auth, "test-org", "test-env", "hosts[0]", "a")
+	require.Nil(t, err)
+	require.Equal(t, []string{"values:\n  hosts:\n    - a\n"}, *updates)
+}
+
+func Test_UnsetEnvironmentValuePreservesDefinition(t *testing.T) {
+	apiClient, updates := newTestDefinitionUpdateServer(t, testCommentedDefinition)
+	auth := NewAuthContext("pul-123")
+
+	_, err := apiClient.UnsetEnvironmentValue(auth, "test-org", "test-env", "zone")
+	require.Nil(t, err)
+	_, err = apiClient.UnsetEnvironmentValueAndPrune(auth, "test-org", "test-env", `pulumiConfig["app:name"]`)
+	require.Nil(t, err)
+
+	require.Equal(t, []string{
+		`# Shared settings.
+imports:
+  - base
+values:
+  # The service port.
+  port: 8080
+  id: 12345678901234567890
+  pulumiConfig:
+    app:name: demo
+`,
+		`# Shared settings.
+imports:
+  - base
+values:
+  # The service port.
+  port: 8080
+  id: 12345678901234567890
+  zone: us-west-2 # primary
+`,
+	}, *updates)
+
+	_, err = apiClient.UnsetEnvironmentValue(auth, "test-org", "test-env", "missing")
+	require.ErrorIs(t, err, ErrPathNotFound)
+	require.Len(t, *updates, 2)
+
+	apiClient, updates = newTestDefinitionUpdateServer(t, "# Shared settings.\nimports:\n  - base\nvalues:\n  hosts: [a]\n")
+	_, err = apiClient.UnsetEnvironmentValueAndPrune(auth, "test-org", "test-env", "hosts[0]")
+	require.Nil(t, err)
+	require.Equal(t, []string{"# Shared settings.\nimports:\n  - base\n"}, *updates)
+}
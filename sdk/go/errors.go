@@ -0,0 +1,72 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrEnvironmentNotFound is matched, using errors.Is, by errors returned when the requested environment does not exist.
+var ErrEnvironmentNotFound = errors.New("environment not found")
+
+// ErrEnvironmentExists is matched, using errors.Is, by errors returned when creating an environment that already exists.
+var ErrEnvironmentExists = errors.New("environment already exists")
+
+// ErrEnvironmentModified is returned when a conditional update is rejected because the environment
+// was modified after it was read.
+var ErrEnvironmentModified = errors.New("environment was modified concurrently")
+
+// ErrPathNotFound is returned when a property path does not refer to an existing value.
+var ErrPathNotFound = errors.New("path not found")
+
+// ErrEmptyEnvironmentYaml is returned when an environment update's YAML is empty or only whitespace.
+// An empty environment should be sent as `values: {}`.
+var ErrEmptyEnvironmentYaml = errors.New("environment YAML is empty; send `values: {}` for an empty environment")
+
+// EnvironmentNotFoundError is returned when the requested environment does not exist.
+// It matches ErrEnvironmentNotFound and unwraps to the underlying GenericOpenAPIError.
+type EnvironmentNotFoundError struct {
+	Org     string
+	EnvName string
+	// Body is the parsed error body returned by the service, if any.
+	Body *Error
+
+	err error
+}
+
+func (e *EnvironmentNotFoundError) Error() string {
+	msg := fmt.Sprintf("environment %s/%s not found", e.Org, e.EnvName)
+	if e.Body != nil && e.Body.Message != "" {
+		msg += ": " + e.Body.Message
+	}
+	return msg
+}
+
+// Is reports whether target is ErrEnvironmentNotFound.
+func (e *EnvironmentNotFoundError) Is(target error) bool {
+	return target == ErrEnvironmentNotFound
+}
+
+// Unwrap returns the underlying API error.
+func (e *EnvironmentNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// wrapNotFound converts a 404 response for the given environment into an EnvironmentNotFoundError.
+// Any other error is returned unchanged.
+func wrapNotFound(err error, resp *http.Response, org, envName string) error {
+	if err == nil || resp == nil || resp.StatusCode != http.StatusNotFound {
+		return err
+	}
+
+	notFound := &EnvironmentNotFoundError{Org: org, EnvName: envName, err: err}
+	var apiErr *GenericOpenAPIError
+	if errors.As(err, &apiErr) {
+		if body, ok := apiErr.Model().(Error); ok {
+			notFound.Body = &body
+		}
+	}
+	return notFound
+}
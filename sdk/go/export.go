@@ -0,0 +1,327 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ExportFormat is an output format supported by Export.
+type ExportFormat string
+
+const (
+	// ExportFormatJSON writes the resolved values as a JSON object.
+	ExportFormatJSON ExportFormat = "json"
+	// ExportFormatYAML writes the resolved values as a YAML mapping.
+	ExportFormatYAML ExportFormat = "yaml"
+	// ExportFormatDotenv writes the values under `environmentVariables` as `NAME="value"` lines.
+	ExportFormatDotenv ExportFormat = "dotenv"
+)
+
+// ExportOptions configures Export.
+type ExportOptions struct {
+	// Format is the output format. If empty, ExportFormatJSON is used.
+	Format ExportFormat
+	// Indent is the number of spaces by which nested JSON and YAML values are indented. For JSON, zero writes the
+	// object on a single line; for YAML, zero uses the default of four spaces. It is ignored for dotenv.
+	Indent int
+}
+
+// CSVOptions configures ExportCSV.
+type CSVOptions struct {
+	// RedactSecrets replaces the values of secrets with "[secret]".
+	RedactSecrets bool
+	// OmitHeader omits the `path,value,secret` header row.
+	OmitHeader bool
+}
+
+// ExportCSV opens and reads the environment with the given name in the given organization and writes its resolved
+// values to w as CSV, with one `path,value,secret` row per leaf value, ordered by path. Paths use property path
+// syntax, e.g. `pulumiConfig.hosts[0]`. Strings are written as-is and other values as JSON.
+func (c *EscClient) ExportCSV(ctx context.Context, org, envName string, w io.Writer, opts *CSVOptions) error {
+	if opts == nil {
+		opts = &CSVOptions{}
+	}
+
+	env, _, err := c.OpenAndReadEnvironment(ctx, org, envName)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if !opts.OmitHeader {
+		if err := cw.Write([]string{"path", "value", "secret"}); err != nil {
+			return err
+		}
+	}
+
+	var writeErr error
+	walkResolvedProperties(env.GetProperties(), func(path string, value any, secret, _ bool) {
+		if writeErr != nil {
+			return
+		}
+
+		var text string
+		if secret && opts.RedactSecrets {
+			text = "[secret]"
+		} else if text, writeErr = exportString(value); writeErr != nil {
+			return
+		}
+		writeErr = cw.Write([]string{path, text, strconv.FormatBool(secret)})
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportJSONLines opens and reads the environment with the given name in the given organization and writes its resolved
+// values to w as JSON Lines, with one `{"path":...,"value":...,"secret":...}` object per leaf value, ordered by path.
+// Paths use property path syntax, e.g. `pulumiConfig.hosts[0]`. Each record is written as soon as it is encoded.
+func (c *EscClient) ExportJSONLines(ctx context.Context, org, envName string, w io.Writer) error {
+	env, _, err := c.OpenAndReadEnvironment(ctx, org, envName)
+	if err != nil {
+		return err
+	}
+
+	type record struct {
+		Path   string `json:"path"`
+		Value  any    `json:"value"`
+		Secret bool   `json:"secret"`
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	var writeErr error
+	walkResolvedProperties(env.GetProperties(), func(path string, value any, secret, _ bool) {
+		if writeErr == nil {
+			writeErr = enc.Encode(record{Path: path, Value: value, Secret: secret})
+		}
+	})
+	return writeErr
+}
+
+// Export opens and reads the environment with the given name in the given organization and writes its resolved
+// values to w in the format given by opts, always ending with a newline. JSON and YAML output contains every value;
+// dotenv output contains only the values under `environmentVariables`, which must all be strings, with each value
+// double-quoted and its backslashes, double quotes, dollar signs, backticks and newlines escaped with a backslash.
+// Object keys and environment variables are always written in sorted order, as resolved values do not record the
+// order in which they were defined. Secrets are written in plaintext.
+func (c *EscClient) Export(ctx context.Context, org, envName string, w io.Writer, opts ExportOptions) error {
+	if opts.Indent < 0 {
+		return fmt.Errorf("invalid indent %d", opts.Indent)
+	}
+	format := opts.Format
+	if format == "" {
+		format = ExportFormatJSON
+	}
+	switch format {
+	case ExportFormatJSON, ExportFormatYAML, ExportFormatDotenv:
+	default:
+		return fmt.Errorf("unsupported export format %q", opts.Format)
+	}
+
+	_, values, err := c.OpenAndReadEnvironment(ctx, org, envName)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case ExportFormatJSON:
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", strings.Repeat(" ", opts.Indent))
+		err = enc.Encode(values)
+	case ExportFormatYAML:
+		enc := yamlv3.NewEncoder(&buf)
+		if opts.Indent != 0 {
+			enc.SetIndent(opts.Indent)
+		}
+		if err = enc.Encode(values); err == nil {
+			err = enc.Close()
+		}
+	case ExportFormatDotenv:
+		err = writeDotenv(&buf, values)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// writeDotenv writes the environment variables within the given resolved values as sorted `NAME="value"` lines.
+func writeDotenv(w io.Writer, values map[string]any) error {
+	_, envVars, _, err := splitEnvironmentSections(values)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(envVars))
+	for name := range envVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "%s=\"%s\"\n", name, dotenvEscaper.Replace(envVars[name])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var dotenvEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`", "\n", `\n`, "\r", `\r`)
+
+// ExportTFVars opens and reads the environment with the given name in the given organization and writes its resolved
+// top-level values to w as a Terraform variable definitions (.tfvars) file, one `name = value` assignment per value,
+// sorted by name. Strings, numbers, bools, arrays and objects are written as HCL literals, with `${` and `%{` escaped
+// so that Terraform does not interpret them as templates. Every top-level name must be a valid HCL identifier.
+//
+// Secrets are written in plaintext, since Terraform needs their values; use ExportMaskedTFVars to preview the output
+// without them.
+func (c *EscClient) ExportTFVars(ctx context.Context, org, envName string, w io.Writer) error {
+	_, values, err := c.OpenAndReadEnvironment(ctx, org, envName)
+	if err != nil {
+		return err
+	}
+	return writeTFVars(w, values)
+}
+
+// ExportMaskedTFVars is like ExportTFVars, but replaces every secret with "[secret]".
+func (c *EscClient) ExportMaskedTFVars(ctx context.Context, org, envName string, w io.Writer) error {
+	env, _, err := c.OpenAndReadEnvironment(ctx, org, envName)
+	if err != nil {
+		return err
+	}
+
+	props := env.GetProperties()
+	values := make(map[string]any, len(props))
+	for k, v := range props {
+		values[k] = maskResolvedValue(v, false)
+	}
+	return writeTFVars(w, values)
+}
+
+// hclIdentifier matches the names that can be assigned in a .tfvars file or used as unquoted object keys.
+var hclIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// writeTFVars writes the given values as sorted `name = value` HCL assignments.
+func writeTFVars(w io.Writer, values map[string]any) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if !hclIdentifier.MatchString(name) {
+			return fmt.Errorf("%q is not a valid Terraform variable name", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		buf.WriteString(name)
+		buf.WriteString(" = ")
+		if err := writeHCLValue(&buf, values[name], ""); err != nil {
+			return fmt.Errorf("%v: %w", name, err)
+		}
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeHCLValue writes a resolved value as an HCL literal. Objects are written one attribute per line, indented
+// beneath the given indent.
+func writeHCLValue(buf *bytes.Buffer, value any, indent string) error {
+	switch value := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		buf.WriteString(hclString(value))
+	case bool:
+		buf.WriteString(strconv.FormatBool(value))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	case []any:
+		buf.WriteByte('[')
+		for i, elem := range value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := writeHCLValue(buf, elem, indent); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		if len(value) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("{\n")
+		for _, k := range keys {
+			buf.WriteString(indent + "  ")
+			if hclIdentifier.MatchString(k) {
+				buf.WriteString(k)
+			} else {
+				buf.WriteString(hclString(k))
+			}
+			buf.WriteString(" = ")
+			if err := writeHCLValue(buf, value[k], indent+"  "); err != nil {
+				return err
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "}")
+	default:
+		return fmt.Errorf("cannot write %T as HCL", value)
+	}
+	return nil
+}
+
+var hclEscaper = strings.NewReplacer(
+	`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "${", "$${", "%{", "%%{")
+
+// hclString quotes a string as an HCL string literal.
+func hclString(s string) string {
+	return `"` + hclEscaper.Replace(s) + `"`
+}
+
+// exportString renders a resolved leaf value as text: strings as-is and other values as JSON.
+func exportString(value any) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	default:
+		bs, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(bs), nil
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testTrace = `{"def": {"environment": "test-env", "begin": {"line": 1, "column": 1, "byte": 0}, "end": {"line": 1, "column": 2, "byte": 1}}}`
+
+const testOpenEnvironment = `{
+  "properties": {
+    "greeting": {"value": "hello, \"world\"", "trace": ` + testTrace + `},
+    "password": {"value": "hunter2", "secret": true, "trace": ` + testTrace + `},
+    "pulumiConfig": {
+      "value": {
+        "port": {"value": 8080, "trace": ` + testTrace + `}
+      },
+      "trace": ` + testTrace + `
+    }
+  }
+}`
+
+func newTestOpenEnvironmentServer(t *testing.T) *EscClient {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/open") {
+			_, _ = w.Write([]byte(`{"id": "1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(testOpenEnvironment))
+	})
+}
+
+func Test_ExportCSV(t *testing.T) {
+	apiClient := newTestOpenEnvironmentServer(t)
+
+	var buf bytes.Buffer
+	err := apiClient.ExportCSV(NewAuthContext("pul-123"), "test-org", "test-env", &buf, &CSVOptions{RedactSecrets: true})
+	require.Nil(t, err)
+	require.Equal(t, `path,value,secret
+greeting,"hello, ""world""",false
+password,[secret],true
+pulumiConfig.port,8080,false
+`, buf.String())
+}
+
+func Test_ExportJSONLines(t *testing.T) {
+	apiClient := newTestOpenEnvironmentServer(t)
+
+	var buf bytes.Buffer
+	err := apiClient.ExportJSONLines(NewAuthContext("pul-123"), "test-org", "test-env", &buf)
+	require.Nil(t, err)
+	require.Equal(t, `{"path":"greeting","value":"hello, \"world\"","secret":false}
+{"path":"password","value":"hunter2","secret":true}
+{"path":"pulumiConfig.port","value":8080,"secret":false}
+`, buf.String())
+}
+
+func Test_Export(t *testing.T) {
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte(`{"id": "1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"properties": {
+			"region": {"value": "us-west-2", "trace": ` + testTrace + `},
+			"environmentVariables": {"value": {
+				"TOKEN": {"value": "a\"b$c\nd", "secret": true, "trace": ` + testTrace + `},
+				"PORT": {"value": "8080", "trace": ` + testTrace + `}
+			}, "trace": ` + testTrace + `}
+		}}`))
+	})
+	auth := NewAuthContext("pul-123")
+
+	cases := []struct {
+		opts     ExportOptions
+		expected string
+	}{
+		{
+			opts:     ExportOptions{},
+			expected: `{"environmentVariables":{"PORT":"8080","TOKEN":"a\"b$c\nd"},"region":"us-west-2"}` + "\n",
+		},
+		{
+			opts: ExportOptions{Format: ExportFormatJSON, Indent: 2},
+			expected: `{
+  "environmentVariables": {
+    "PORT": "8080",
+    "TOKEN": "a\"b$c\nd"
+  },
+  "region": "us-west-2"
+}
+`,
+		},
+		{
+			opts: ExportOptions{Format: ExportFormatYAML, Indent: 2},
+			expected: `environmentVariables:
+  PORT: "8080"
+  TOKEN: |-
+    a"b$c
+    d
+region: us-west-2
+`,
+		},
+		{
+			opts: ExportOptions{Format: ExportFormatDotenv},
+			expected: `PORT="8080"
+TOKEN="a\"b\$c\nd"
+`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(string(c.opts.Format), func(t *testing.T) {
+			var buf bytes.Buffer
+			err := apiClient.Export(auth, "test-org", "test-env", &buf, c.opts)
+			require.Nil(t, err)
+			require.Equal(t, c.expected, buf.String())
+		})
+	}
+
+	var buf bytes.Buffer
+	err := apiClient.Export(auth, "test-org", "test-env", &buf, ExportOptions{Format: "toml"})
+	require.EqualError(t, err, `unsupported export format "toml"`)
+	err = apiClient.Export(auth, "test-org", "test-env", &buf, ExportOptions{Indent: -1})
+	require.Error(t, err)
+	require.Empty(t, buf.String())
+}
+
+func Test_ExportTFVars(t *testing.T) {
+	apiClient := newTestOpenEnvironmentServer(t)
+	auth := NewAuthContext("pul-123")
+
+	var buf bytes.Buffer
+	err := apiClient.ExportTFVars(auth, "test-org", "test-env", &buf)
+	require.Nil(t, err)
+	require.Equal(t, `greeting = "hello, \"world\""
+password = "hunter2"
+pulumiConfig = {
+  port = 8080
+}
+`, buf.String())
+
+	buf.Reset()
+	err = apiClient.ExportMaskedTFVars(auth, "test-org", "test-env", &buf)
+	require.Nil(t, err)
+	require.Contains(t, buf.String(), `password = "[secret]"`)
+}
+
+func Test_WriteTFVars(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeTFVars(&buf, map[string]any{
+		"template": "${var.x} %{if} $5 \\ \n",
+		"ratio":    0.25,
+		"enabled":  true,
+		"nothing":  nil,
+		"zones":    []any{"a", float64(2), []any{}},
+		"tags": map[string]any{
+			"team":     "core",
+			"aws:role": map[string]any{"arn": "arn:aws:iam::1"},
+			"empty":    map[string]any{},
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, `enabled = true
+nothing = null
+ratio = 0.25
+tags = {
+  "aws:role" = {
+    arn = "arn:aws:iam::1"
+  }
+  empty = {}
+  team = "core"
+}
+template = "$${var.x} %%{if} $5 \\ \n"
+zones = ["a", 2, []]
+`, buf.String())
+
+	err = writeTFVars(&buf, map[string]any{"aws:region": "us-west-2"})
+	require.EqualError(t, err, `"aws:region" is not a valid Terraform variable name`)
+}
@@ -0,0 +1,91 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import "sort"
+
+// IsBuiltin reports whether the expression is a call to a builtin function, e.g. `fn::open` or `fn::secret`, and if
+// so returns the function's name.
+func (e *Expr) IsBuiltin() (name string, ok bool) {
+	if e == nil || e.Builtin == nil {
+		return "", false
+	}
+	return e.Builtin.Name, true
+}
+
+// LiteralValue reports whether the expression is a literal and if so returns its value, which may be any JSON value:
+// nil, a bool, a float64, a string, a []any or a map[string]any. Expressions are literals if they are not
+// interpolations, symbols, lists, objects or builtin calls, so a literal null is reported with a nil value.
+// The method is not named Literal because that is the name of the field holding the value.
+func (e *Expr) LiteralValue() (any, bool) {
+	if e == nil {
+		return nil, false
+	}
+	if e.Literal != nil {
+		return e.Literal, true
+	}
+	isLiteral := e.Interpolate == nil && e.Symbol == nil && e.List == nil && e.Object == nil && e.Builtin == nil
+	return nil, isLiteral
+}
+
+// Interpolations returns the property paths referenced by the expression's interpolations, e.g. `db.host` for
+// `${db.host}:5432`, in the order in which they appear. A symbol, i.e. a string consisting of a single
+// interpolation, is reported as one path. The interpolations of nested expressions are not included; use Walk to
+// visit them.
+func (e *Expr) Interpolations() []string {
+	if e == nil {
+		return nil
+	}
+
+	var paths []string
+	if len(e.Symbol) != 0 {
+		paths = append(paths, propertyAccessorPath(e.Symbol))
+	}
+	for _, interp := range e.Interpolate {
+		if len(interp.Value) != 0 {
+			paths = append(paths, propertyAccessorPath(interp.Value))
+		}
+	}
+	return paths
+}
+
+// Walk calls fn for the expression and each of its nested expressions, in depth-first order: list elements in order,
+// object properties sorted by key, and the argument of a builtin call. If fn returns false, the expressions nested
+// within the one it was called for are skipped.
+func (e *Expr) Walk(fn func(*Expr) bool) {
+	if e == nil || !fn(e) {
+		return
+	}
+
+	for i := range e.List {
+		e.List[i].Walk(fn)
+	}
+	if e.Object != nil {
+		obj := *e.Object
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := obj[k]
+			v.Walk(fn)
+		}
+	}
+	if e.Builtin != nil {
+		e.Builtin.Arg.Walk(fn)
+	}
+}
+
+// propertyAccessorPath renders the accessors of an interpolation as a property path.
+func propertyAccessorPath(accessors []PropertyAccessor) string {
+	path := ""
+	for _, accessor := range accessors {
+		if accessor.Index != nil {
+			path = appendPropertyIndex(path, int(*accessor.Index))
+		} else {
+			path = appendPropertyKey(path, accessor.Key)
+		}
+	}
+	return path
+}
@@ -0,0 +1,75 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testExprRange = `{"environment": "test-env", "begin": {"line": 1, "column": 1, "byte": 0}, "end": {"line": 1, "column": 2, "byte": 1}}`
+
+func Test_Expr(t *testing.T) {
+	var expr Expr
+	require.Nil(t, json.Unmarshal([]byte(`{"object": {
+		"aws": {"builtin": {"name": "fn::open", "arg": {"object": {
+			"provider": {"literal": "aws-login"},
+			"inputs": {"object": {"duration": {"literal": 3600}}}
+		}}}},
+		"url": {"interpolate": [
+			{"text": "https://", "value": [{"key": "host", "range": `+testExprRange+`}]},
+			{"text": ":", "value": [
+				{"key": "ports", "range": `+testExprRange+`},
+				{"index": 0, "key": "", "range": `+testExprRange+`}
+			]}
+		]},
+		"region": {"symbol": [{"key": "aws", "range": `+testExprRange+`}, {"key": "region", "range": `+testExprRange+`}]},
+		"flags": {"list": [{"literal": false}, {}]}
+	}}`), &expr))
+	obj := expr.GetObject()
+
+	aws := obj["aws"]
+	name, ok := aws.IsBuiltin()
+	require.True(t, ok)
+	require.Equal(t, "fn::open", name)
+	_, ok = aws.LiteralValue()
+	require.False(t, ok)
+
+	flags := obj["flags"]
+	value, ok := flags.List[0].LiteralValue()
+	require.True(t, ok)
+	require.Equal(t, false, value)
+	value, ok = flags.List[1].LiteralValue()
+	require.True(t, ok)
+	require.Nil(t, value)
+	_, ok = flags.IsBuiltin()
+	require.False(t, ok)
+
+	url, region := obj["url"], obj["region"]
+	require.Equal(t, []string{"host", "ports[0]"}, url.Interpolations())
+	require.Equal(t, []string{"aws.region"}, region.Interpolations())
+	require.Empty(t, aws.Interpolations())
+
+	var builtins []string
+	var literals []any
+	expr.Walk(func(e *Expr) bool {
+		if name, ok := e.IsBuiltin(); ok {
+			builtins = append(builtins, name)
+		}
+		if value, ok := e.LiteralValue(); ok {
+			literals = append(literals, value)
+		}
+		return true
+	})
+	require.Equal(t, []string{"fn::open"}, builtins)
+	require.Equal(t, []any{float64(3600), "aws-login", false, nil}, literals)
+
+	var visited int
+	expr.Walk(func(e *Expr) bool {
+		visited++
+		return e.Builtin == nil
+	})
+	require.Equal(t, 7, visited)
+}
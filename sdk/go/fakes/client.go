@@ -0,0 +1,343 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+// Package fakes provides an in-memory implementation of esc_sdk.EscClientAPI for use in tests.
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/ghodss/yaml.v1"
+
+	esc_sdk "github.com/pulumi/esc-sdk/sdk/go"
+)
+
+// Client is an in-memory implementation of esc_sdk.EscClientAPI. It stores environment definitions as given and
+// does not evaluate them: opening an environment resolves its `values` as written, without applying imports,
+// interpolations or builtin functions, except that `fn::secret` values are resolved to their plaintext and marked
+// secret. Open sessions never expire. The zero value is not usable; create clients with NewClient.
+//
+// A Client is safe for concurrent use.
+type Client struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	envs     map[string]*environment
+	sessions map[string]string
+	nextID   int
+}
+
+type environment struct {
+	org, name         string
+	yaml              string
+	created, modified string
+}
+
+var _ esc_sdk.EscClientAPI = (*Client)(nil)
+
+// NewClient creates an empty fake client.
+func NewClient() *Client {
+	return &Client{
+		now:      time.Now,
+		envs:     map[string]*environment{},
+		sessions: map[string]string{},
+	}
+}
+
+// SetEnvironment creates or replaces the environment with the given name in the given organization with the given
+// YAML definition, e.g. to seed the fake before a test.
+func (c *Client) SetEnvironment(org, envName, yamlDoc string) error {
+	if _, err := parseDefinition(yamlDoc); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	env, ok := c.envs[environmentKey(org, envName)]
+	if !ok {
+		env = c.newEnvironment(org, envName)
+	}
+	env.yaml, env.modified = yamlDoc, c.timestamp()
+	return nil
+}
+
+// ListEnvironments lists the environments in the given organization, sorted by name, in a single page.
+func (c *Client) ListEnvironments(ctx context.Context, org string, continuationToken *string) (*esc_sdk.OrgEnvironments, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	envs := []esc_sdk.OrgEnvironment{}
+	for _, env := range c.envs {
+		if env.org == org {
+			org := env.org
+			envs = append(envs, esc_sdk.OrgEnvironment{
+				Organization: &org,
+				Name:         env.name,
+				Created:      env.created,
+				Modified:     env.modified,
+			})
+		}
+	}
+	sort.Slice(envs, func(i, j int) bool { return envs[i].Name < envs[j].Name })
+	return &esc_sdk.OrgEnvironments{Environments: envs}, nil
+}
+
+// GetEnvironment returns the definition of the environment with the given name in the given organization, along with
+// its YAML.
+func (c *Client) GetEnvironment(ctx context.Context, org, envName string) (*esc_sdk.EnvironmentDefinition, string, error) {
+	env, err := c.environment(org, envName)
+	if err != nil {
+		return nil, "", err
+	}
+	def, err := parseDefinition(env.yaml)
+	if err != nil {
+		return nil, "", err
+	}
+	return def, env.yaml, nil
+}
+
+// CreateEnvironment creates an empty environment. Like esc_sdk.EscClient, it returns an error matching
+// esc_sdk.ErrEnvironmentExists if the environment already exists.
+func (c *Client) CreateEnvironment(ctx context.Context, org, envName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.envs[environmentKey(org, envName)]; ok {
+		return fmt.Errorf("%w: %s/%s", esc_sdk.ErrEnvironmentExists, org, envName)
+	}
+	c.newEnvironment(org, envName)
+	return nil
+}
+
+// UpdateEnvironmentYaml replaces the definition of an existing environment with the given YAML.
+func (c *Client) UpdateEnvironmentYaml(ctx context.Context, org, envName, yamlDoc string) (*esc_sdk.EnvironmentDiagnostics, error) {
+	if strings.TrimSpace(yamlDoc) == "" {
+		return nil, esc_sdk.ErrEmptyEnvironmentYaml
+	}
+	if _, err := parseDefinition(yamlDoc); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	env, ok := c.envs[environmentKey(org, envName)]
+	if !ok {
+		return nil, notFound(org, envName)
+	}
+	env.yaml, env.modified = yamlDoc, c.timestamp()
+	return &esc_sdk.EnvironmentDiagnostics{}, nil
+}
+
+// UpdateEnvironment replaces the definition of an existing environment with the given definition.
+func (c *Client) UpdateEnvironment(
+	ctx context.Context,
+	org, envName string,
+	env *esc_sdk.EnvironmentDefinition,
+) (*esc_sdk.EnvironmentDiagnostics, error) {
+	yamlDoc, err := esc_sdk.MarshalEnvironmentDefinition(env)
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateEnvironmentYaml(ctx, org, envName, yamlDoc)
+}
+
+// DeleteEnvironment deletes an environment and any sessions opened for it.
+func (c *Client) DeleteEnvironment(ctx context.Context, org, envName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := environmentKey(org, envName)
+	if _, ok := c.envs[key]; !ok {
+		return notFound(org, envName)
+	}
+	delete(c.envs, key)
+	for id, envKey := range c.sessions {
+		if envKey == key {
+			delete(c.sessions, id)
+		}
+	}
+	return nil
+}
+
+// OpenEnvironment opens an environment, returning a new session ID.
+func (c *Client) OpenEnvironment(ctx context.Context, org, envName string) (*esc_sdk.OpenEnvironment, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := environmentKey(org, envName)
+	if _, ok := c.envs[key]; !ok {
+		return nil, notFound(org, envName)
+	}
+	c.nextID++
+	id := strconv.Itoa(c.nextID)
+	c.sessions[id] = key
+	return &esc_sdk.OpenEnvironment{Id: id}, nil
+}
+
+// ReadOpenEnvironment returns the values of the environment opened with the given session ID, both as resolved
+// properties and as plain Go values.
+func (c *Client) ReadOpenEnvironment(ctx context.Context, org, envName, openEnvID string) (*esc_sdk.Environment, map[string]any, error) {
+	values, err := c.openValues(org, envName, openEnvID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	props := make(map[string]esc_sdk.Value, len(values))
+	plain := make(map[string]any, len(values))
+	for k, v := range values {
+		value := resolveValue(v, false)
+		props[k] = *value
+		plain[k] = value.Decode()
+	}
+	return &esc_sdk.Environment{Properties: &props}, plain, nil
+}
+
+// OpenAndReadEnvironment opens and reads an environment.
+func (c *Client) OpenAndReadEnvironment(ctx context.Context, org, envName string) (*esc_sdk.Environment, map[string]any, error) {
+	openInfo, err := c.OpenEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.ReadOpenEnvironment(ctx, org, envName, openInfo.Id)
+}
+
+// ReadEnvironmentProperty returns the value at the given property path, e.g. `a.b[0]["c.d"]`, within the
+// environment opened with the given session ID. It returns esc_sdk.ErrPathNotFound if there is no value at the path.
+func (c *Client) ReadEnvironmentProperty(ctx context.Context, org, envName, openEnvID, propPath string) (*esc_sdk.Value, any, error) {
+	values, err := c.openValues(org, envName, openEnvID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segments, err := esc_sdk.ParsePropertyPath(propPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var current any = values
+	secret := false
+	for _, segment := range segments {
+		current, err = lookup(current, segment, &secret)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", err, propPath)
+		}
+	}
+
+	value := resolveValue(current, secret)
+	return value, value.Decode(), nil
+}
+
+func (c *Client) newEnvironment(org, envName string) *environment {
+	now := c.timestamp()
+	env := &environment{org: org, name: envName, yaml: "{}\n", created: now, modified: now}
+	c.envs[environmentKey(org, envName)] = env
+	return env
+}
+
+func (c *Client) environment(org, envName string) (environment, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	env, ok := c.envs[environmentKey(org, envName)]
+	if !ok {
+		return environment{}, notFound(org, envName)
+	}
+	return *env, nil
+}
+
+// openValues returns the values of the environment opened with the given session ID as plain values.
+func (c *Client) openValues(org, envName, openEnvID string) (map[string]any, error) {
+	c.mu.Lock()
+	key, ok := c.sessions[openEnvID]
+	c.mu.Unlock()
+	if !ok || key != environmentKey(org, envName) {
+		return nil, fmt.Errorf("open session %q not found for environment %s/%s", openEnvID, org, envName)
+	}
+
+	env, err := c.environment(org, envName)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(env.yaml), &doc); err != nil {
+		return nil, err
+	}
+	values, _ := doc["values"].(map[string]any)
+	if values == nil {
+		values = map[string]any{}
+	}
+	return values, nil
+}
+
+func (c *Client) timestamp() string {
+	return c.now().UTC().Format(time.RFC3339)
+}
+
+func environmentKey(org, envName string) string {
+	return org + "/" + envName
+}
+
+func notFound(org, envName string) error {
+	return &esc_sdk.EnvironmentNotFoundError{Org: org, EnvName: envName}
+}
+
+func parseDefinition(yamlDoc string) (*esc_sdk.EnvironmentDefinition, error) {
+	var def esc_sdk.EnvironmentDefinition
+	if err := yaml.Unmarshal([]byte(yamlDoc), &def); err != nil {
+		return nil, fmt.Errorf("invalid environment definition: %w", err)
+	}
+	return &def, nil
+}
+
+// resolveValue converts a value as written in a definition into a resolved value. `fn::secret` values resolve to
+// their plaintext, marked secret along with everything nested within them.
+func resolveValue(value any, secret bool) *esc_sdk.Value {
+	if obj, ok := value.(map[string]any); ok && len(obj) == 1 {
+		if plaintext, ok := obj["fn::secret"]; ok {
+			return resolveValue(plaintext, true)
+		}
+	}
+
+	resolved := &esc_sdk.Value{Value: value}
+	if secret {
+		resolved.Secret = &secret
+	}
+	switch value := value.(type) {
+	case map[string]any:
+		obj := make(map[string]esc_sdk.Value, len(value))
+		for k, v := range value {
+			obj[k] = *resolveValue(v, secret)
+		}
+		resolved.Value = obj
+	case []any:
+		arr := make([]any, len(value))
+		for i, v := range value {
+			arr[i] = resolveValue(v, secret)
+		}
+		resolved.Value = arr
+	}
+	return resolved
+}
+
+// lookup returns the value of the given path segment within value, looking through any `fn::secret` and recording
+// in secret if it does.
+func lookup(value any, segment any, secret *bool) (any, error) {
+	if obj, ok := value.(map[string]any); ok && len(obj) == 1 {
+		if plaintext, ok := obj["fn::secret"]; ok {
+			value, *secret = plaintext, true
+		}
+	}
+
+	switch segment := segment.(type) {
+	case string:
+		if obj, ok := value.(map[string]any); ok {
+			if v, ok := obj[segment]; ok {
+				return v, nil
+			}
+		}
+	case int:
+		if arr, ok := value.([]any); ok && segment < len(arr) {
+			return arr[segment], nil
+		}
+	}
+	return nil, esc_sdk.ErrPathNotFound
+}
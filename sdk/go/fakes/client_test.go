@@ -0,0 +1,70 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package fakes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	esc_sdk "github.com/pulumi/esc-sdk/sdk/go"
+)
+
+func Test_Client(t *testing.T) {
+	ctx := context.Background()
+	var client esc_sdk.EscClientAPI = NewClient()
+
+	require.Nil(t, client.CreateEnvironment(ctx, "test-org", "app"))
+	require.ErrorIs(t, client.CreateEnvironment(ctx, "test-org", "app"), esc_sdk.ErrEnvironmentExists)
+
+	_, err := client.UpdateEnvironmentYaml(ctx, "test-org", "app", `imports: [base]
+values:
+  region: us-west-2
+  ports: [80, 443]
+  db:
+    password:
+      fn::secret: hunter2
+`)
+	require.Nil(t, err)
+	_, err = client.UpdateEnvironmentYaml(ctx, "test-org", "app", "")
+	require.ErrorIs(t, err, esc_sdk.ErrEmptyEnvironmentYaml)
+
+	def, _, err := client.GetEnvironment(ctx, "test-org", "app")
+	require.Nil(t, err)
+	require.Equal(t, []string{"base"}, def.Imports)
+
+	env, values, err := client.OpenAndReadEnvironment(ctx, "test-org", "app")
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{
+		"region": "us-west-2",
+		"ports":  []any{float64(80), float64(443)},
+		"db":     map[string]any{"password": "hunter2"},
+	}, values)
+	password := env.GetProperties()["db"].Value.(map[string]esc_sdk.Value)["password"]
+	require.True(t, password.GetSecret())
+
+	openInfo, err := client.OpenEnvironment(ctx, "test-org", "app")
+	require.Nil(t, err)
+	prop, value, err := client.ReadEnvironmentProperty(ctx, "test-org", "app", openInfo.Id, "ports[1]")
+	require.Nil(t, err)
+	require.Equal(t, float64(443), value)
+	require.False(t, prop.GetSecret())
+	prop, value, err = client.ReadEnvironmentProperty(ctx, "test-org", "app", openInfo.Id, `["db"].password`)
+	require.Nil(t, err)
+	require.Equal(t, "hunter2", value)
+	require.True(t, prop.GetSecret())
+	_, _, err = client.ReadEnvironmentProperty(ctx, "test-org", "app", openInfo.Id, "db.user")
+	require.ErrorIs(t, err, esc_sdk.ErrPathNotFound)
+
+	envs, err := client.ListEnvironments(ctx, "test-org", nil)
+	require.Nil(t, err)
+	require.Len(t, envs.Environments, 1)
+	require.Equal(t, "app", envs.Environments[0].Name)
+
+	require.Nil(t, client.DeleteEnvironment(ctx, "test-org", "app"))
+	_, _, err = client.GetEnvironment(ctx, "test-org", "app")
+	require.ErrorIs(t, err, esc_sdk.ErrEnvironmentNotFound)
+	_, _, err = client.ReadOpenEnvironment(ctx, "test-org", "app", openInfo.Id)
+	require.Error(t, err)
+}
@@ -0,0 +1,112 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AsFlatMap flattens the resolved values returned by ReadOpenEnvironment and OpenAndReadEnvironment into a
+// single-level map whose keys join the path to each leaf with sep, e.g. `pulumiConfig.aws:region` or `hosts.0`.
+// The result can be loaded directly by config libraries such as koanf or Viper.
+//
+// Array elements are keyed by their index. Empty objects and arrays are kept as leaves so that they are not lost.
+// Occurrences of sep or of a backslash within a key are escaped with a backslash so that distinct paths never
+// produce the same flattened key.
+func AsFlatMap(values map[string]any, sep string) map[string]any {
+	flat := make(map[string]any)
+	for k, v := range values {
+		flattenValue(flat, escapeFlatKey(k, sep), v, sep)
+	}
+	return flat
+}
+
+func flattenValue(flat map[string]any, prefix string, value any, sep string) {
+	switch value := value.(type) {
+	case map[string]any:
+		if len(value) == 0 {
+			flat[prefix] = value
+			return
+		}
+		for k, v := range value {
+			flattenValue(flat, prefix+sep+escapeFlatKey(k, sep), v, sep)
+		}
+	case []any:
+		if len(value) == 0 {
+			flat[prefix] = value
+			return
+		}
+		for i, v := range value {
+			flattenValue(flat, prefix+sep+strconv.Itoa(i), v, sep)
+		}
+	default:
+		flat[prefix] = value
+	}
+}
+
+func escapeFlatKey(key, sep string) string {
+	if !strings.Contains(key, `\`) && (sep == "" || !strings.Contains(key, sep)) {
+		return key
+	}
+	key = strings.ReplaceAll(key, `\`, `\\`)
+	if sep != "" {
+		key = strings.ReplaceAll(key, sep, `\`+sep)
+	}
+	return key
+}
+
+// walkResolvedProperties calls fn for every leaf value within the given resolved properties, as returned in
+// Environment.Properties by ReadOpenEnvironment, along with its property path and whether it is secret or unknown.
+// Values nested within a secret or unknown value are secret or unknown as well. Empty objects and arrays are
+// reported as leaves.
+func walkResolvedProperties(props map[string]Value, fn func(path string, value any, secret, unknown bool)) {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := props[k]
+		walkResolvedValue(appendPropertyKey("", k), &v, false, false, fn)
+	}
+}
+
+func walkResolvedValue(path string, value any, secret, unknown bool, fn func(path string, value any, secret, unknown bool)) {
+	switch val := value.(type) {
+	case *Value:
+		if val == nil {
+			fn(path, nil, secret, unknown)
+			return
+		}
+		walkResolvedValue(path, val.Value, secret || val.GetSecret(), unknown || val.GetUnknown(), fn)
+	case Value:
+		walkResolvedValue(path, &val, secret, unknown, fn)
+	case map[string]Value:
+		if len(val) == 0 {
+			fn(path, map[string]any{}, secret, unknown)
+			return
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := val[k]
+			walkResolvedValue(appendPropertyKey(path, k), &v, secret, unknown, fn)
+		}
+	case []any:
+		if len(val) == 0 {
+			fn(path, []any{}, secret, unknown)
+			return
+		}
+		for i, v := range val {
+			walkResolvedValue(appendPropertyIndex(path, i), v, secret, unknown, fn)
+		}
+	default:
+		fn(path, value, secret, unknown)
+	}
+}
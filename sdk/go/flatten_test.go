@@ -0,0 +1,30 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AsFlatMap(t *testing.T) {
+	values := map[string]any{
+		"foo": "bar",
+		"pulumiConfig": map[string]any{
+			"aws:region": "us-west-2",
+			"a.b":        true,
+		},
+		"hosts": []any{"x", map[string]any{"name": "y"}},
+		"empty": map[string]any{},
+	}
+
+	require.Equal(t, map[string]any{
+		"foo":                     "bar",
+		"pulumiConfig.aws:region": "us-west-2",
+		`pulumiConfig.a\.b`:       true,
+		"hosts.0":                 "x",
+		"hosts.1.name":            "y",
+		"empty":                   map[string]any{},
+	}, AsFlatMap(values, "."))
+}
@@ -0,0 +1,48 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"net/http"
+)
+
+// contextRequestHeaders holds the headers attached to a context by WithRequestHeaders.
+var contextRequestHeaders = contextKey("requestHeaders")
+
+// reservedRequestHeaders are the headers that WithRequestHeaders cannot set, because the client manages them.
+var reservedRequestHeaders = map[string]bool{
+	"Authorization":    true,
+	"Content-Encoding": true,
+	"Content-Length":   true,
+	"Content-Type":     true,
+	"Host":             true,
+}
+
+// WithRequestHeaders returns a context that adds the given headers to every request made with it, e.g. a trace ID
+// for a single call. Unlike Configuration.DefaultHeader, the headers only apply to calls made with the returned
+// context and are safe to use concurrently. Headers already attached to ctx are kept unless overridden.
+// Headers that the client manages, such as Authorization and Content-Type, are ignored.
+func WithRequestHeaders(ctx context.Context, headers map[string]string) context.Context {
+	merged := make(map[string]string, len(headers))
+	if existing, ok := ctx.Value(contextRequestHeaders).(map[string]string); ok {
+		for k, v := range existing {
+			merged[k] = v
+		}
+	}
+	for k, v := range headers {
+		k = http.CanonicalHeaderKey(k)
+		if !reservedRequestHeaders[k] {
+			merged[k] = v
+		}
+	}
+	return context.WithValue(ctx, contextRequestHeaders, merged)
+}
+
+// setContextHeaders sets the headers attached to ctx by WithRequestHeaders, replacing any default values.
+func setContextHeaders(ctx context.Context, header http.Header) {
+	headers, _ := ctx.Value(contextRequestHeaders).(map[string]string)
+	for k, v := range headers {
+		header.Set(k, v)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithRequestHeaders(t *testing.T) {
+	var header http.Header
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}, WithDefaultHeader("X-Feature", "default"))
+	serverURL := apiClient.rawClient.cfg.Servers[0].URL
+
+	ctx := WithRequestHeaders(NewAuthContext("pul-123"), map[string]string{"x-trace-id": "abc", "X-Feature": "on"})
+	ctx = WithRequestHeaders(ctx, map[string]string{"Authorization": "token stolen", "X-Other": "1"})
+
+	_, err := apiClient.OpenEnvironment(ctx, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "abc", header.Get("X-Trace-Id"))
+	require.Equal(t, []string{"on"}, header.Values("X-Feature"))
+	require.Equal(t, "1", header.Get("X-Other"))
+	require.Equal(t, "token pul-123", header.Get("Authorization"))
+
+	_, _, err = apiClient.rawRequest(ctx, "Test", http.MethodGet, serverURL, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, "abc", header.Get("X-Trace-Id"))
+	require.Equal(t, "token pul-123", header.Get("Authorization"))
+
+	_, err = apiClient.OpenEnvironment(NewAuthContext("pul-123"), "test-org", "test-env")
+	require.Nil(t, err)
+	require.Empty(t, header.Get("X-Trace-Id"))
+	require.Equal(t, "default", header.Get("X-Feature"))
+}
+
+func Test_AcceptLanguage(t *testing.T) {
+	var header http.Header
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	})
+	serverURL := apiClient.rawClient.cfg.Servers[0].URL
+	auth := NewAuthContext("pul-123")
+
+	_, err := apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Empty(t, header.Values("Accept-Language"))
+
+	apiClient.rawClient.cfg.Language = "fr-CA"
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, []string{"fr-CA"}, header.Values("Accept-Language"))
+
+	_, _, err = apiClient.rawRequest(auth, "Test", http.MethodGet, serverURL, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, []string{"fr-CA"}, header.Values("Accept-Language"))
+
+	ctx := WithRequestHeaders(auth, map[string]string{"Accept-Language": "de"})
+	_, err = apiClient.OpenEnvironment(ctx, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, []string{"de"}, header.Values("Accept-Language"))
+}
@@ -0,0 +1,17 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient starts a test server that serves requests with the given handler and returns a client that sends
+// requests to it, with the given options applied. The server is closed when the test finishes.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *EscClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClientWithOptions(server.URL, nil, opts...)
+}
@@ -0,0 +1,192 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxConcurrentReads bounds the number of environments read at once by methods that read many environments.
+const maxConcurrentReads = 8
+
+// GetEnvironmentImports returns the environments directly imported by the environment with the given name
+// in the given organization, in import order.
+func (c *EscClient) GetEnvironmentImports(ctx context.Context, org, envName string) ([]string, error) {
+	env, _, err := c.GetEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.Imports, nil
+}
+
+// GetEnvironmentImportGraph returns the import graph of all environments in the given organization,
+// mapping each environment's name to the names of the environments it directly imports.
+func (c *EscClient) GetEnvironmentImportGraph(ctx context.Context, org string) (map[string][]string, error) {
+	return c.environmentImportGraph(ctx, org, c.GetEnvironmentImports)
+}
+
+// GetEnvironmentImporters returns the environments in the given organization that directly import the environment
+// with the given name, sorted by name. There is no reverse index, so this reads the definition of every environment
+// in the organization, at most maxConcurrentReads at a time; use a CachingClient to avoid re-reading definitions
+// that have not changed when calling it repeatedly.
+func (c *EscClient) GetEnvironmentImporters(ctx context.Context, org, envName string) ([]EnvironmentRef, error) {
+	graph, err := c.GetEnvironmentImportGraph(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	return importers(graph, envName), nil
+}
+
+// environmentImportGraph builds the import graph of all environments in the given organization, reading the imports
+// of each environment with getImports.
+func (c *EscClient) environmentImportGraph(
+	ctx context.Context,
+	org string,
+	getImports func(ctx context.Context, org, envName string) ([]string, error),
+) (map[string][]string, error) {
+	envs, err := c.ListAllEnvironments(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	graph := make(map[string][]string, len(envs))
+	err = runBounded(ctx, envs, maxConcurrentReads, func(ctx context.Context, env OrgEnvironment) error {
+		imports, err := getImports(ctx, org, env.Name)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		graph[env.Name] = imports
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// importers returns the environments in the given import graph that directly import envName, sorted by name.
+func importers(graph map[string][]string, envName string) []EnvironmentRef {
+	var refs []EnvironmentRef
+	for name, imports := range graph {
+		for _, imp := range imports {
+			if imp == envName {
+				refs = append(refs, EnvironmentRef{EnvName: name})
+				break
+			}
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].EnvName < refs[j].EnvName })
+	return refs
+}
+
+// ErrImportCycle is matched, using errors.Is, by errors reporting that a definition would create an import cycle.
+var ErrImportCycle = errors.New("import cycle")
+
+// ImportCycleError reports that a definition would create an import cycle.
+type ImportCycleError struct {
+	// Cycle is the path of the cycle, beginning and ending with the same environment.
+	Cycle []string
+}
+
+func (e *ImportCycleError) Error() string {
+	return "cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// Is reports whether target is ErrImportCycle.
+func (e *ImportCycleError) Is(target error) bool {
+	return target == ErrImportCycle
+}
+
+// DetectImportCycle reports whether giving the environment with the given name in the given organization the
+// proposed definition would create an import cycle. The imports of imported environments are fetched as needed.
+// If a cycle would be created, its path is returned, beginning and ending with envName; otherwise nil is returned.
+// Imports of environments that do not exist are ignored.
+func (c *EscClient) DetectImportCycle(ctx context.Context, org string, def *EnvironmentDefinition, envName string) ([]string, error) {
+	if def == nil {
+		return nil, nil
+	}
+
+	imports := map[string][]string{envName: def.Imports}
+	getImports := func(name string) ([]string, error) {
+		if imps, ok := imports[name]; ok {
+			return imps, nil
+		}
+		imps, err := c.GetEnvironmentImports(ctx, org, name)
+		if err != nil && !errors.Is(err, ErrEnvironmentNotFound) {
+			return nil, err
+		}
+		imports[name] = imps
+		return imps, nil
+	}
+
+	visited := map[string]bool{}
+	var visit func(path []string) ([]string, error)
+	visit = func(path []string) ([]string, error) {
+		imps, err := getImports(path[len(path)-1])
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range imps {
+			if imp == envName {
+				return append(path, imp), nil
+			}
+			if visited[imp] {
+				continue
+			}
+			visited[imp] = true
+			if cycle, err := visit(append(path, imp)); cycle != nil || err != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+
+	return visit([]string{envName})
+}
+
+// AddImport appends the environment with the given name to the definition's imports unless it is already imported.
+// An environment that is already imported keeps its position, and so its precedence.
+func (d *EnvironmentDefinition) AddImport(name string) {
+	for _, imp := range d.Imports {
+		if imp == name {
+			return
+		}
+	}
+	d.Imports = append(d.Imports, name)
+}
+
+// NormalizeImports removes surrounding whitespace, empty names and duplicates from the definition's imports.
+//
+// Imports are not sorted, since their order determines which values take precedence when they are merged. When an
+// environment is imported more than once, its last occurrence is kept: values from later imports override earlier
+// ones, so the last occurrence is the one that determines the result, and removing the others leaves the merged
+// values unchanged.
+func (d *EnvironmentDefinition) NormalizeImports() {
+	if len(d.Imports) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool, len(d.Imports))
+	normalized := make([]string, 0, len(d.Imports))
+	for i := len(d.Imports) - 1; i >= 0; i-- {
+		name := strings.TrimSpace(d.Imports[i])
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	for i, j := 0, len(normalized)-1; i < j; i, j = i+1, j-1 {
+		normalized[i], normalized[j] = normalized[j], normalized[i]
+	}
+	d.Imports = normalized
+}
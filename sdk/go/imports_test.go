@@ -0,0 +1,44 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Importers(t *testing.T) {
+	graph := map[string][]string{
+		"base":    nil,
+		"app":     {"base", "aws"},
+		"aws":     {"base"},
+		"staging": {"app"},
+		"dev":     {"base", "base"},
+	}
+
+	require.Equal(t, []EnvironmentRef{{EnvName: "app"}, {EnvName: "aws"}, {EnvName: "dev"}}, importers(graph, "base"))
+	require.Equal(t, []EnvironmentRef{{EnvName: "staging"}}, importers(graph, "app"))
+	require.Nil(t, importers(graph, "staging"))
+}
+
+func Test_AddImport(t *testing.T) {
+	def := &EnvironmentDefinition{}
+	def.AddImport("base")
+	def.AddImport("aws")
+	def.AddImport("base")
+	require.Equal(t, []string{"base", "aws"}, def.Imports)
+}
+
+func Test_NormalizeImports(t *testing.T) {
+	def := &EnvironmentDefinition{Imports: []string{"base", " aws ", "", "app", "base", "aws"}}
+	def.NormalizeImports()
+	require.Equal(t, []string{"app", "base", "aws"}, def.Imports)
+
+	def.NormalizeImports()
+	require.Equal(t, []string{"app", "base", "aws"}, def.Imports)
+
+	empty := &EnvironmentDefinition{}
+	empty.NormalizeImports()
+	require.Nil(t, empty.Imports)
+}
@@ -0,0 +1,51 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GetByJSONPointer returns the value at the given RFC 6901 JSON pointer, e.g. `/pulumiConfig/hosts/0`, within the
+// resolved values returned by ReadOpenEnvironment and OpenAndReadEnvironment. The empty pointer refers to the values
+// themselves. The second result is false if the pointer is malformed or does not refer to an existing value.
+func GetByJSONPointer(values map[string]any, pointer string) (any, bool) {
+	if pointer == "" {
+		return values, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+
+	var current any = values
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			if token == "" || (len(token) > 1 && token[0] == '0') {
+				return nil, false
+			}
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// escapeJSONPointerToken escapes a key for use as an RFC 6901 JSON pointer reference token.
+func escapeJSONPointerToken(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,40 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetByJSONPointer(t *testing.T) {
+	values := map[string]any{
+		"pulumiConfig": map[string]any{
+			"hosts": []any{"a", "b"},
+			"a/b":   1.0,
+			"m~n":   2.0,
+		},
+	}
+
+	v, ok := GetByJSONPointer(values, "/pulumiConfig/hosts/1")
+	require.True(t, ok)
+	require.Equal(t, "b", v)
+
+	v, ok = GetByJSONPointer(values, "/pulumiConfig/a~1b")
+	require.True(t, ok)
+	require.Equal(t, 1.0, v)
+
+	v, ok = GetByJSONPointer(values, "/pulumiConfig/m~0n")
+	require.True(t, ok)
+	require.Equal(t, 2.0, v)
+
+	v, ok = GetByJSONPointer(values, "")
+	require.True(t, ok)
+	require.Equal(t, values, v)
+
+	for _, pointer := range []string{"pulumiConfig", "/missing", "/pulumiConfig/hosts/2", "/pulumiConfig/hosts/01", "/pulumiConfig/hosts/-"} {
+		_, ok := GetByJSONPointer(values, pointer)
+		require.False(t, ok, pointer)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"strings"
+)
+
+// minLeakSecretLength is the length below which secrets are not searched for. Shorter secrets, e.g. "1" or "on",
+// appear by chance in too many plaintext values for a match to suggest a leak.
+const minLeakSecretLength = 4
+
+// LeakFinding reports a non-secret value that contains the value of a secret.
+type LeakFinding struct {
+	// Path is the property path of the non-secret value, e.g. `environmentVariables.DATABASE_URL`.
+	Path string
+	// SecretPath is the property path of the secret whose value appears within the value at Path.
+	SecretPath string
+}
+
+// DetectSecretLeaks opens and reads the environment with the given name in the given organization and reports every
+// non-secret string value that contains the value of a secret string verbatim, e.g. because the secret was
+// interpolated into a plaintext field. Secrets shorter than four characters are not searched for, as they would
+// match unrelated values. Findings are ordered by path.
+func (c *EscClient) DetectSecretLeaks(ctx context.Context, org, envName string) ([]LeakFinding, error) {
+	env, _, err := c.OpenAndReadEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+	return findSecretLeaks(env.GetProperties()), nil
+}
+
+func findSecretLeaks(props map[string]Value) []LeakFinding {
+	type stringValue struct {
+		path  string
+		value string
+	}
+
+	var secrets, plaintexts []stringValue
+	walkResolvedProperties(props, func(path string, value any, secret, _ bool) {
+		s, ok := value.(string)
+		if !ok || s == "" {
+			return
+		}
+		if secret {
+			if len(s) >= minLeakSecretLength {
+				secrets = append(secrets, stringValue{path, s})
+			}
+		} else {
+			plaintexts = append(plaintexts, stringValue{path, s})
+		}
+	})
+
+	var findings []LeakFinding
+	for _, p := range plaintexts {
+		for _, s := range secrets {
+			if strings.Contains(p.value, s.value) {
+				findings = append(findings, LeakFinding{Path: p.path, SecretPath: s.path})
+			}
+		}
+	}
+	return findings
+}
@@ -0,0 +1,32 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FindSecretLeaks(t *testing.T) {
+	secret := true
+	props := map[string]Value{
+		"password": {Value: "hunter2", Secret: &secret},
+		"tokens":   {Value: []any{&Value{Value: "tok-123", Secret: &secret}}},
+		"pin":      {Value: "123", Secret: &secret},
+		"enabled":  {Value: "on", Secret: &secret},
+		"environmentVariables": {Value: map[string]Value{
+			"DATABASE_URL": {Value: "postgres://admin:hunter2@db"},
+			"PASSWORD":     {Value: "hunter2", Secret: &secret},
+			"TOKEN_HEADER": {Value: "Bearer tok-123"},
+			"USER":         {Value: "admin"},
+			"MODE":         {Value: "production"},
+		}},
+	}
+
+	require.Equal(t, []LeakFinding{
+		{Path: "environmentVariables.DATABASE_URL", SecretPath: "environmentVariables.PASSWORD"},
+		{Path: "environmentVariables.DATABASE_URL", SecretPath: "password"},
+		{Path: "environmentVariables.TOKEN_HEADER", SecretPath: "tokens[0]"},
+	}, findSecretLeaks(props))
+}
@@ -0,0 +1,125 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/ghodss/yaml.v1"
+)
+
+// CheckEnvironmentYamlWithLocalImports checks the given environment YAML definition for errors as
+// CheckEnvironmentYaml does, except that imports of the environments named in localImports are resolved from the
+// given YAML definitions rather than from the server. This allows changes to a chain of imports to be validated
+// before any of them have been pushed.
+//
+// The local imports are inlined: the checked document is synthesized from the given YAML by merging in the values
+// of each local import, transitively, as GetEffectiveEnvironmentDefinition does, and keeping only the imports that
+// are not local. Those remaining imports must exist on the server, and they are applied before all of the inlined
+// values, so their precedence may differ from that of the original import order. Because the server only sees the
+// synthesized document, diagnostics for inlined values refer to that document rather than to the local import that
+// defined them, and the document is subject to the server's usual limits on definition size. An import cycle among
+// the local imports is reported with an ImportCycleError.
+func (c *EscClient) CheckEnvironmentYamlWithLocalImports(
+	ctx context.Context,
+	org, yamlDoc string,
+	localImports map[string]string,
+) (*CheckEnvironment, error) {
+	doc, err := parseLocalDefinition("", yamlDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, values, err := inlineLocalImports(doc, localImports, nil, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	if len(imports) != 0 {
+		doc["imports"] = imports
+	} else {
+		delete(doc, "imports")
+	}
+	if len(values) != 0 {
+		doc["values"] = values
+	} else {
+		delete(doc, "values")
+	}
+
+	bs, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return c.CheckEnvironmentYaml(ctx, org, string(bs))
+}
+
+// parseLocalDefinition parses the given environment YAML definition into plain values. name is the name of the
+// environment used in errors; it is empty for the definition being checked.
+func parseLocalDefinition(name, yamlDoc string) (map[string]any, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(yamlDoc), &doc); err != nil {
+		if name != "" {
+			return nil, fmt.Errorf("local import %q: %w", name, err)
+		}
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return doc, nil
+}
+
+// inlineLocalImports returns the remote imports and merged values of the given definition, inlining the values of
+// its local imports. path holds the local imports being inlined, in order to detect cycles, and seen records the
+// remote imports already returned so that each is only imported once.
+func inlineLocalImports(
+	doc map[string]any,
+	localImports map[string]string,
+	path []string,
+	seen map[string]bool,
+) ([]any, map[string]any, error) {
+	var remote []any
+	merged := map[string]any{}
+
+	imports, _ := doc["imports"].([]any)
+	for _, imp := range imports {
+		name, ok := imp.(string)
+		if !ok {
+			// Imports with options, e.g. `- env: {merge: false}`, are left to the server.
+			remote = append(remote, imp)
+			continue
+		}
+
+		local, ok := localImports[name]
+		if !ok {
+			if !seen[name] {
+				seen[name] = true
+				remote = append(remote, name)
+			}
+			continue
+		}
+
+		for i, p := range path {
+			if p == name {
+				cycle := append(append([]string{}, path[i:]...), name)
+				return nil, nil, &ImportCycleError{Cycle: cycle}
+			}
+		}
+
+		importDoc, err := parseLocalDefinition(name, local)
+		if err != nil {
+			return nil, nil, err
+		}
+		importRemote, importValues, err := inlineLocalImports(importDoc, localImports, append(path, name), seen)
+		if err != nil {
+			return nil, nil, err
+		}
+		remote = append(remote, importRemote...)
+		merged = mergeValues(merged, importValues).(map[string]any)
+	}
+
+	if values, ok := doc["values"].(map[string]any); ok {
+		merged = mergeValues(merged, values).(map[string]any)
+	}
+	return remote, merged, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ghodss/yaml.v1"
+)
+
+func Test_CheckEnvironmentYamlWithLocalImports(t *testing.T) {
+	var checked map[string]any
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		require.Nil(t, yaml.Unmarshal(body, &checked))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+	auth := NewAuthContext("pul-123")
+
+	localImports := map[string]string{
+		"base":   "imports: [shared]\nvalues:\n  region: us-east-1\n  db: {host: db.internal, port: 5432}\n",
+		"common": "imports: [base, shared]\nvalues:\n  db: {port: 6432}\n",
+	}
+	doc := "imports: [common, remote]\nvalues:\n  region: us-west-2\n  app: ${db.host}\n"
+
+	_, err := apiClient.CheckEnvironmentYamlWithLocalImports(auth, "test-org", doc, localImports)
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{
+		"imports": []any{"shared", "remote"},
+		"values": map[string]any{
+			"region": "us-west-2",
+			"db":     map[string]any{"host": "db.internal", "port": float64(6432)},
+			"app":    "${db.host}",
+		},
+	}, checked)
+
+	localImports["base"] = "imports: [common]\n"
+	_, err = apiClient.CheckEnvironmentYamlWithLocalImports(auth, "test-org", doc, localImports)
+	require.ErrorIs(t, err, ErrImportCycle)
+	require.Equal(t, []string{"common", "base", "common"}, err.(*ImportCycleError).Cycle)
+}
@@ -0,0 +1,94 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"fmt"
+)
+
+// maskedSecret is the display form of a secret value.
+const maskedSecret = "[secret]"
+
+// MaskedValue returns the display form of the resolved value at the given property path, e.g.
+// `environmentVariables.DB_PASSWORD`, with every secret replaced by "[secret]", as shown by `esc env get`.
+// Strings are returned as-is and other values as JSON. Secretness is taken from the resolved values themselves;
+// values nested within a secret are masked along with it. If the path does not refer to a value, ErrPathNotFound
+// is returned.
+func (e *Environment) MaskedValue(path string) (string, error) {
+	segments, err := ParsePropertyPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	var value any = e.GetProperties()
+	secret := false
+	for _, segment := range segments {
+		switch container := unwrapResolvedValue(value, &secret).(type) {
+		case map[string]Value:
+			key, ok := segment.(string)
+			if !ok {
+				return "", fmt.Errorf("%w: %v", ErrPathNotFound, path)
+			}
+			v, ok := container[key]
+			if !ok {
+				return "", fmt.Errorf("%w: %v", ErrPathNotFound, path)
+			}
+			value = &v
+		case []any:
+			index, ok := segment.(int)
+			if !ok || index < 0 || index >= len(container) {
+				return "", fmt.Errorf("%w: %v", ErrPathNotFound, path)
+			}
+			value = container[index]
+		default:
+			return "", fmt.Errorf("%w: %v", ErrPathNotFound, path)
+		}
+	}
+
+	return exportString(maskResolvedValue(value, secret))
+}
+
+// unwrapResolvedValue strips any *Value or Value wrappers from value, recording in secret whether any of them
+// was secret.
+func unwrapResolvedValue(value any, secret *bool) any {
+	for {
+		switch v := value.(type) {
+		case *Value:
+			if v == nil {
+				return nil
+			}
+			*secret = *secret || v.GetSecret()
+			value = v.Value
+		case Value:
+			*secret = *secret || v.GetSecret()
+			value = v.Value
+		default:
+			return value
+		}
+	}
+}
+
+// maskResolvedValue converts a resolved value to plain values, replacing secrets with their display form.
+func maskResolvedValue(value any, secret bool) any {
+	value = unwrapResolvedValue(value, &secret)
+	if secret {
+		return maskedSecret
+	}
+
+	switch v := value.(type) {
+	case map[string]Value:
+		masked := make(map[string]any, len(v))
+		for k, elem := range v {
+			masked[k] = maskResolvedValue(elem, false)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(v))
+		for i, elem := range v {
+			masked[i] = maskResolvedValue(elem, false)
+		}
+		return masked
+	default:
+		return value
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MaskedValue(t *testing.T) {
+	secret := true
+	env := &Environment{Properties: &map[string]Value{
+		"environmentVariables": {Value: map[string]Value{
+			"USER":     {Value: "admin"},
+			"PASSWORD": {Value: "hunter2", Secret: &secret},
+		}},
+		"hosts": {Value: []any{&Value{Value: "a"}, &Value{Value: "b", Secret: &secret}}},
+		"creds": {Value: map[string]Value{"token": {Value: "tok"}}, Secret: &secret},
+		"port":  {Value: 8080.0},
+	}}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "environmentVariables.USER", expected: "admin"},
+		{path: "environmentVariables.PASSWORD", expected: "[secret]"},
+		{path: "environmentVariables", expected: `{"PASSWORD":"[secret]","USER":"admin"}`},
+		{path: "hosts", expected: `["a","[secret]"]`},
+		{path: "hosts[1]", expected: "[secret]"},
+		{path: "creds.token", expected: "[secret]"},
+		{path: "port", expected: "8080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			actual, err := env.MaskedValue(tt.path)
+			require.Nil(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+
+	for _, path := range []string{"missing", "hosts[2]", "port.value", "environmentVariables[0]"} {
+		_, err := env.MaskedValue(path)
+		require.ErrorIs(t, err, ErrPathNotFound, path)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrInvalidName is matched, using errors.Is, by errors returned by ValidateEnvironmentRef.
+var ErrInvalidName = errors.New("invalid name")
+
+// maxNameLength is the maximum length of organization and environment names.
+const maxNameLength = 100
+
+// validName matches the characters permitted in organization and environment names.
+var validName = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// ValidateEnvironmentRef checks the given organization and environment names against the service's naming rules:
+// names must be non-empty, at most 100 characters long, and contain only letters, digits, hyphens, underscores and
+// periods. The returned error names the invalid component and matches ErrInvalidName.
+func ValidateEnvironmentRef(org, envName string) error {
+	if err := validateName("organization", org); err != nil {
+		return err
+	}
+	return validateName("environment", envName)
+}
+
+func validateName(component, name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("%w: %s name must not be empty", ErrInvalidName, component)
+	case len(name) > maxNameLength:
+		return fmt.Errorf("%w: %s name %q must be at most %d characters long", ErrInvalidName, component, name, maxNameLength)
+	case !validName.MatchString(name):
+		return fmt.Errorf("%w: %s name %q may only contain letters, digits, hyphens, underscores and periods",
+			ErrInvalidName, component, name)
+	default:
+		return nil
+	}
+}
+
+// checkEnvironmentRef validates the given names if the client's configuration enables ValidateNames.
+func (c *EscClient) checkEnvironmentRef(org, envName string) error {
+	if !c.rawClient.cfg.ValidateNames {
+		return nil
+	}
+	return ValidateEnvironmentRef(org, envName)
+}
@@ -0,0 +1,216 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateEnvironmentRef(t *testing.T) {
+	require.Nil(t, ValidateEnvironmentRef("my-org", "app_1.staging"))
+
+	tests := []struct {
+		org, envName string
+		expected     string
+	}{
+		{org: "", envName: "env", expected: "organization name must not be empty"},
+		{org: "org", envName: "", expected: "environment name must not be empty"},
+		{org: "org", envName: "proj/env", expected: `environment name "proj/env" may only contain`},
+		{org: "my org", envName: "env", expected: `organization name "my org" may only contain`},
+		{org: "org", envName: strings.Repeat("a", 101), expected: "must be at most 100 characters long"},
+	}
+	for _, tt := range tests {
+		err := ValidateEnvironmentRef(tt.org, tt.envName)
+		require.ErrorIs(t, err, ErrInvalidName)
+		require.ErrorContains(t, err, tt.expected)
+	}
+}
+
+func Test_ValidateNames(t *testing.T) {
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+	}, func(cfg *Configuration) {
+		cfg.ValidateNames = true
+	})
+	cachingClient := NewCachingClient(apiClient.rawClient.cfg, CacheOptions{})
+	auth := NewAuthContext("pul-123")
+
+	const org, env = "test-org", "a/b"
+	tests := map[string]func() error{
+		"GetEnvironment": func() error {
+			_, _, err := apiClient.GetEnvironment(auth, org, env)
+			return err
+		},
+		"GetEnvironmentRaw": func() error {
+			_, err := apiClient.GetEnvironmentRaw(auth, org, env)
+			return err
+		},
+		"GetEnvironmentJSON": func() error {
+			_, _, err := apiClient.GetEnvironmentJSON(auth, org, env)
+			return err
+		},
+		"GetEnvironmentAtVersion": func() error {
+			_, _, err := apiClient.GetEnvironmentAtVersion(auth, org, env, "1")
+			return err
+		},
+		"GetEnvironmentETag": func() error {
+			_, err := apiClient.GetEnvironmentETag(auth, org, env)
+			return err
+		},
+		"GetEnvironmentYamlCanonical": func() error {
+			_, err := apiClient.GetEnvironmentYamlCanonical(auth, org, env)
+			return err
+		},
+		"OpenEnvironment": func() error {
+			_, err := apiClient.OpenEnvironment(auth, org, env)
+			return err
+		},
+		"OpenEnvironmentWithDiagnostics": func() error {
+			_, _, err := apiClient.OpenEnvironmentWithDiagnostics(auth, org, env)
+			return err
+		},
+		"OpenEnvironmentAtVersion": func() error {
+			_, err := apiClient.OpenEnvironmentAtVersion(auth, org, env, "1")
+			return err
+		},
+		"ReadOpenEnvironment": func() error {
+			_, _, err := apiClient.ReadOpenEnvironment(auth, org, env, "1")
+			return err
+		},
+		"OpenAndReadEnvironment": func() error {
+			_, _, err := apiClient.OpenAndReadEnvironment(auth, org, env)
+			return err
+		},
+		"OpenAndReadEnvironmentAtTime": func() error {
+			_, _, err := apiClient.OpenAndReadEnvironmentAtTime(auth, org, env, time.Now())
+			return err
+		},
+		"ReadEnvironmentProperty": func() error {
+			_, _, err := apiClient.ReadEnvironmentProperty(auth, org, env, "1", "greeting")
+			return err
+		},
+		"ResolveProperty": func() error {
+			_, err := apiClient.ResolveProperty(auth, org, env, "greeting")
+			return err
+		},
+		"ReadOpenEnvironmentFile": func() error {
+			_, err := apiClient.ReadOpenEnvironmentFile(auth, org, env, "1", "files.cert")
+			return err
+		},
+		"ReadOpenEnvironmentPropertySchema": func() error {
+			_, err := apiClient.ReadOpenEnvironmentPropertySchema(auth, org, env, "1", "greeting")
+			return err
+		},
+		"OpenSession": func() error {
+			_, err := apiClient.OpenSession(auth, org, env)
+			return err
+		},
+		"CreateEnvironment": func() error {
+			return apiClient.CreateEnvironment(auth, "", "env")
+		},
+		"CreateEnvironmentIfNotExists": func() error {
+			_, err := apiClient.CreateEnvironmentIfNotExists(auth, org, env)
+			return err
+		},
+		"UpdateEnvironmentYaml": func() error {
+			_, err := apiClient.UpdateEnvironmentYaml(auth, org, env, "values: {}")
+			return err
+		},
+		"UpdateEnvironment": func() error {
+			_, err := apiClient.UpdateEnvironment(auth, org, env, &EnvironmentDefinition{})
+			return err
+		},
+		"UpdateEnvironmentDryRun": func() error {
+			_, err := apiClient.UpdateEnvironmentDryRun(auth, org, env, &EnvironmentDefinition{})
+			return err
+		},
+		"SetEnvironmentValue": func() error {
+			_, err := apiClient.SetEnvironmentValue(auth, org, env, "greeting", "hi")
+			return err
+		},
+		"UnsetEnvironmentValue": func() error {
+			_, err := apiClient.UnsetEnvironmentValue(auth, org, env, "greeting")
+			return err
+		},
+		"DeleteEnvironment": func() error {
+			return apiClient.DeleteEnvironment(auth, org, "")
+		},
+		"DecryptEnvironment": func() error {
+			_, _, err := apiClient.DecryptEnvironment(auth, org, env)
+			return err
+		},
+		"ListEnvironmentRevisions": func() error {
+			_, err := apiClient.ListEnvironmentRevisions(auth, org, env)
+			return err
+		},
+		"ListEnvironmentRevisionsPaginated": func() error {
+			_, err := apiClient.ListEnvironmentRevisionsPaginated(auth, org, env, 10, 2)
+			return err
+		},
+		"ListAllEnvironmentRevisions": func() error {
+			_, err := apiClient.ListAllEnvironmentRevisions(auth, org, env)
+			return err
+		},
+		"EnvironmentRevisionAtTime": func() error {
+			_, err := apiClient.EnvironmentRevisionAtTime(auth, org, env, time.Now())
+			return err
+		},
+		"GetEnvironmentChangelog": func() error {
+			_, err := apiClient.GetEnvironmentChangelog(auth, org, env, 3)
+			return err
+		},
+		"ListEnvironmentRevisionTags": func() error {
+			_, err := apiClient.ListEnvironmentRevisionTags(auth, org, env)
+			return err
+		},
+		"ListEnvironmentRevisionTagsPaginated": func() error {
+			_, err := apiClient.ListEnvironmentRevisionTagsPaginated(auth, org, env, "", 2)
+			return err
+		},
+		"GetRevisionTagMap": func() error {
+			_, err := apiClient.GetRevisionTagMap(auth, org, env)
+			return err
+		},
+		"GetEnvironmentRevisionTag": func() error {
+			_, err := apiClient.GetEnvironmentRevisionTag(auth, org, env, "stable")
+			return err
+		},
+		"CreateEnvironmentRevisionTag": func() error {
+			return apiClient.CreateEnvironmentRevisionTag(auth, org, env, "stable", 1)
+		},
+		"UpdateEnvironmentRevisionTag": func() error {
+			return apiClient.UpdateEnvironmentRevisionTag(auth, org, env, "stable", 1)
+		},
+		"UpsertEnvironmentRevisionTag": func() error {
+			_, err := apiClient.UpsertEnvironmentRevisionTag(auth, org, env, "stable", 1)
+			return err
+		},
+		"PromoteRevisionTag": func() error {
+			return apiClient.PromoteRevisionTag(auth, org, env, "stable")
+		},
+		"DeleteEnvironmentRevisionTag": func() error {
+			return apiClient.DeleteEnvironmentRevisionTag(auth, org, env, "stable")
+		},
+		"WatchEnvironment": func() error {
+			_, errs := apiClient.WatchEnvironment(auth, org, env, time.Millisecond)
+			return <-errs
+		},
+		"CopyEnvironmentToOrg": func() error {
+			return apiClient.CopyEnvironmentToOrg(auth, org, env, org, "dest", false)
+		},
+		"CachingClient.GetEnvironment": func() error {
+			_, _, err := cachingClient.GetEnvironment(auth, "my org", "env")
+			return err
+		},
+	}
+	for name, run := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, run(), ErrInvalidName)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import "context"
+
+// OpenAndReadEnvironmentNoSecrets resolves the environment with the given name in the given organization without
+// opening it, for logging and debugging. The open endpoint always evaluates secrets and dynamic providers, so the
+// environment's definition is instead evaluated with CheckEnvironmentYaml, which does not run providers.
+//
+// Secrets are returned as "[secret]" in the values. Values produced by `fn::open` providers are unknown, and
+// are returned as the service reports them. Because the definition is checked anonymously rather than as the named
+// environment, context values that refer to the current environment may differ from those seen when opening it.
+func (c *EscClient) OpenAndReadEnvironmentNoSecrets(ctx context.Context, org, envName string) (*Environment, map[string]any, error) {
+	_, yaml, err := c.GetEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	check, err := c.CheckEnvironmentYaml(ctx, org, yaml)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := (&EnvironmentDiagnostics{Diagnostics: check.Diagnostics}).AsError(); err != nil {
+		return nil, nil, err
+	}
+
+	env := &Environment{
+		Exprs:            check.Exprs,
+		Properties:       check.Properties,
+		Schema:           check.Schema,
+		ExecutionContext: check.ExecutionContext,
+	}
+
+	propertyMap := env.GetProperties()
+	for k, v := range propertyMap {
+		v.Value = mapValues(v.Value)
+		propertyMap[k] = v
+	}
+
+	values := make(map[string]any, len(propertyMap))
+	for k, v := range propertyMap {
+		values[k] = maskResolvedValue(v, false)
+	}
+
+	return env, values, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OpenAndReadEnvironmentNoSecrets(t *testing.T) {
+	const definition = "values:\n  greeting: hello\n  password:\n    fn::secret: hunter2\n"
+	const invalid = "values:\n  bad: ${missing}\n"
+
+	var requests []string
+	var checked []string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch r.URL.Path {
+		case "/environments/test-org/test-env":
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = w.Write([]byte(definition))
+		case "/environments/test-org/invalid-env":
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = w.Write([]byte(invalid))
+		case "/environments/test-org/yaml/check":
+			checked = append(checked, string(body))
+			w.Header().Set("Content-Type", "application/json")
+			if string(body) == invalid {
+				_, _ = w.Write([]byte(`{"diagnostics": [{"summary": "unknown property \"missing\""}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(testOpenEnvironment))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	auth := NewAuthContext("pul-123")
+
+	env, values, err := apiClient.OpenAndReadEnvironmentNoSecrets(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{
+		"greeting":     `hello, "world"`,
+		"password":     "[secret]",
+		"pulumiConfig": map[string]any{"port": 8080.0},
+	}, values)
+	password := env.GetProperties()["password"]
+	require.True(t, password.GetSecret())
+	require.Equal(t, []string{definition}, checked)
+	require.Equal(t, []string{
+		"GET /environments/test-org/test-env",
+		"POST /environments/test-org/yaml/check",
+	}, requests)
+
+	_, _, err = apiClient.OpenAndReadEnvironmentNoSecrets(auth, "test-org", "invalid-env")
+	require.EqualError(t, err, `unknown property "missing"`)
+
+	_, _, err = apiClient.OpenAndReadEnvironmentNoSecrets(auth, "test-org", "missing")
+	require.ErrorIs(t, err, ErrEnvironmentNotFound)
+}
@@ -0,0 +1,228 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ParsePropertyPath parses a property path such as `a.b[0]["c.d"]` into its segments.
+// Object keys are returned as strings and array indices as ints.
+func ParsePropertyPath(path string) ([]any, error) {
+	var segments []any
+	for i := 0; i < len(path); {
+		switch {
+		case path[i] == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path %q: missing closing bracket", path)
+			}
+			inner := path[i+1 : i+end]
+			if strings.HasPrefix(inner, `"`) {
+				// Quoted keys may themselves contain brackets, so look for the closing quote instead.
+				closeQuote := strings.Index(path[i+2:], `"]`)
+				if closeQuote == -1 {
+					return nil, fmt.Errorf("invalid path %q: unterminated quoted key", path)
+				}
+				key, err := strconv.Unquote(path[i+1 : i+2+closeQuote+1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid path %q: %w", path, err)
+				}
+				segments = append(segments, key)
+				i += 2 + closeQuote + 2
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid path %q: invalid index %q", path, inner)
+				}
+				segments = append(segments, index)
+				i += end + 1
+			}
+		case path[i] == '.':
+			if i == 0 || i == len(path)-1 || path[i+1] == '.' || path[i+1] == '[' {
+				return nil, fmt.Errorf("invalid path %q: empty key", path)
+			}
+			i++
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end == -1 {
+				end = len(path) - i
+			}
+			segments = append(segments, path[i:i+end])
+			i += end
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid path %q: path is empty", path)
+	}
+	return segments, nil
+}
+
+// getPropertyPath returns the value at the given path within root. ErrPathNotFound is returned if nothing exists at
+// the path.
+func getPropertyPath(root any, path []any) (any, error) {
+	for _, segment := range path {
+		switch segment := segment.(type) {
+		case string:
+			obj, ok := root.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%w: key %q", ErrPathNotFound, segment)
+			}
+			child, exists := obj[segment]
+			if !exists {
+				return nil, fmt.Errorf("%w: key %q", ErrPathNotFound, segment)
+			}
+			root = child
+		case int:
+			arr, ok := root.([]any)
+			if !ok || segment >= len(arr) {
+				return nil, fmt.Errorf("%w: index %d", ErrPathNotFound, segment)
+			}
+			root = arr[segment]
+		default:
+			return nil, fmt.Errorf("invalid path segment %v", segment)
+		}
+	}
+	return root, nil
+}
+
+// setPropertyNode sets the value at the given path within root, creating intermediate objects as needed.
+// An array index may refer to an existing element or to the position just past the end of the array,
+// in which case the value is appended. root may be nil or a null node. The updated root is returned; nodes along the
+// path are edited in place, so that the comments, key order and styles of the rest of the document are preserved.
+func setPropertyNode(root *yamlv3.Node, path []any, value *yamlv3.Node) (*yamlv3.Node, error) {
+	if len(path) == 0 {
+		if root != nil {
+			value.HeadComment, value.LineComment, value.FootComment = root.HeadComment, root.LineComment, root.FootComment
+		}
+		return value, nil
+	}
+
+	switch segment := path[0].(type) {
+	case string:
+		if isNullNode(root) {
+			root = &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+		}
+		if root.Kind != yamlv3.MappingNode {
+			return nil, fmt.Errorf("cannot set key %q: parent is not an object", segment)
+		}
+		i := mappingKeyIndex(root, segment)
+		if i == -1 {
+			child, err := setPropertyNode(nil, path[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			key := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: segment}
+			root.Content = append(root.Content, key, child)
+			return root, nil
+		}
+		child, err := setPropertyNode(root.Content[i+1], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		root.Content[i+1] = child
+		return root, nil
+	case int:
+		if isNullNode(root) {
+			root = &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+		}
+		if root.Kind != yamlv3.SequenceNode {
+			return nil, fmt.Errorf("cannot set index %d: parent is not an array", segment)
+		}
+		switch {
+		case segment < len(root.Content):
+			child, err := setPropertyNode(root.Content[segment], path[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			root.Content[segment] = child
+		case segment == len(root.Content):
+			child, err := setPropertyNode(nil, path[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			root.Content = append(root.Content, child)
+		default:
+			return nil, fmt.Errorf("cannot set index %d: array has %d elements", segment, len(root.Content))
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("invalid path segment %v", segment)
+	}
+}
+
+// unsetPropertyNode removes the value at the given path within root, editing it in place. Removing an array element
+// shifts the elements that follow it. If prune is true, objects and arrays left empty by the removal are removed as
+// well. ErrPathNotFound is returned if nothing exists at the path.
+func unsetPropertyNode(root *yamlv3.Node, path []any, prune bool) error {
+	var i int
+	switch segment := path[0].(type) {
+	case string:
+		if root == nil || root.Kind != yamlv3.MappingNode {
+			return fmt.Errorf("%w: key %q", ErrPathNotFound, segment)
+		}
+		if i = mappingKeyIndex(root, segment); i == -1 {
+			return fmt.Errorf("%w: key %q", ErrPathNotFound, segment)
+		}
+		i++
+	case int:
+		if root == nil || root.Kind != yamlv3.SequenceNode || segment >= len(root.Content) {
+			return fmt.Errorf("%w: index %d", ErrPathNotFound, segment)
+		}
+		i = segment
+	default:
+		return fmt.Errorf("invalid path segment %v", segment)
+	}
+
+	if len(path) > 1 {
+		child := root.Content[i]
+		if err := unsetPropertyNode(child, path[1:], prune); err != nil {
+			return err
+		}
+		if !prune || !isEmptyNode(child) {
+			return nil
+		}
+	}
+
+	// For objects, i is the index of the value; remove its key as well.
+	if root.Kind == yamlv3.MappingNode {
+		root.Content = append(root.Content[:i-1], root.Content[i+1:]...)
+	} else {
+		root.Content = append(root.Content[:i], root.Content[i+1:]...)
+	}
+	return nil
+}
+
+// mappingKeyIndex returns the index within the content of the given mapping node of the given key, or -1 if the
+// mapping has no such key.
+func mappingKeyIndex(mapping *yamlv3.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func isNullNode(n *yamlv3.Node) bool {
+	return n == nil || n.Kind == yamlv3.ScalarNode && n.Tag == "!!null"
+}
+
+func isEmptyNode(n *yamlv3.Node) bool {
+	return (n.Kind == yamlv3.MappingNode || n.Kind == yamlv3.SequenceNode) && len(n.Content) == 0
+}
+
+func isEmptyContainer(v any) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
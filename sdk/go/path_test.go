@@ -0,0 +1,103 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+func Test_ParsePropertyPath(t *testing.T) {
+	segments, err := ParsePropertyPath(`pulumiConfig.aws:region`)
+	require.Nil(t, err)
+	require.Equal(t, []any{"pulumiConfig", "aws:region"}, segments)
+
+	segments, err = ParsePropertyPath(`hosts[1].name["with.dots"]`)
+	require.Nil(t, err)
+	require.Equal(t, []any{"hosts", 1, "name", "with.dots"}, segments)
+
+	for _, path := range []string{"", ".a", "a.", "a..b", "a[", "a[x]", `a["b]`} {
+		_, err := ParsePropertyPath(path)
+		require.Error(t, err, path)
+	}
+}
+
+// parseTestNode parses the given YAML document and returns its top-level node.
+func parseTestNode(t *testing.T, doc string) *yamlv3.Node {
+	var node yamlv3.Node
+	require.Nil(t, yamlv3.Unmarshal([]byte(doc), &node))
+	return node.Content[0]
+}
+
+func encodeTestNode(t *testing.T, node *yamlv3.Node) string {
+	var buf strings.Builder
+	enc := yamlv3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	require.Nil(t, enc.Encode(node))
+	return buf.String()
+}
+
+func Test_SetPropertyNode(t *testing.T) {
+	root := parseTestNode(t, "# hosts\nhosts: [a] # inline\nport: 8080\n")
+
+	updated, err := setPropertyNode(root, []any{"pulumiConfig", "foo"}, &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: "bar"})
+	require.Nil(t, err)
+	updated, err = setPropertyNode(updated, []any{"hosts", 1}, &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: "b"})
+	require.Nil(t, err)
+	updated, err = setPropertyNode(updated, []any{"port"}, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!int", Value: "9090"})
+	require.Nil(t, err)
+	require.Equal(t, "# hosts\nhosts: [a, b] # inline\nport: 9090\npulumiConfig:\n  foo: bar\n", encodeTestNode(t, updated))
+
+	_, err = setPropertyNode(updated, []any{"hosts", 3}, &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: "d"})
+	require.Error(t, err)
+	_, err = setPropertyNode(updated, []any{"hosts", "key"}, &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: "d"})
+	require.Error(t, err)
+
+	updated, err = setPropertyNode(parseTestNode(t, "null"), []any{"a", 0}, &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: "x"})
+	require.Nil(t, err)
+	require.Equal(t, "a:\n  - x\n", encodeTestNode(t, updated))
+}
+
+func Test_UnsetPropertyNode(t *testing.T) {
+	const doc = "a:\n  b:\n    c: 1\n# the hosts\nhosts: [x, y, z]\n"
+
+	root := parseTestNode(t, doc)
+	require.Nil(t, unsetPropertyNode(root, []any{"hosts", 1}, false))
+	require.Equal(t, "a:\n  b:\n    c: 1\n# the hosts\nhosts: [x, z]\n", encodeTestNode(t, root))
+
+	root = parseTestNode(t, doc)
+	require.Nil(t, unsetPropertyNode(root, []any{"a", "b", "c"}, false))
+	require.Equal(t, "a:\n  b: {}\n# the hosts\nhosts: [x, y, z]\n", encodeTestNode(t, root))
+
+	root = parseTestNode(t, doc)
+	require.Nil(t, unsetPropertyNode(root, []any{"a", "b", "c"}, true))
+	require.Equal(t, "# the hosts\nhosts: [x, y, z]\n", encodeTestNode(t, root))
+
+	require.ErrorIs(t, unsetPropertyNode(parseTestNode(t, doc), []any{"a", "missing"}, false), ErrPathNotFound)
+	require.ErrorIs(t, unsetPropertyNode(parseTestNode(t, doc), []any{"hosts", 3}, false), ErrPathNotFound)
+}
+
+func Test_GetPropertyPath(t *testing.T) {
+	root := map[string]any{
+		"a":     map[string]any{"b": map[string]any{"c": 1}},
+		"hosts": []any{"x", map[string]any{"name": "y"}},
+	}
+
+	value, err := getPropertyPath(root, []any{"a", "b"})
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{"c": 1}, value)
+
+	value, err = getPropertyPath(root, []any{"hosts", 1, "name"})
+	require.Nil(t, err)
+	require.Equal(t, "y", value)
+
+	_, err = getPropertyPath(root, []any{"a", "missing"})
+	require.ErrorIs(t, err, ErrPathNotFound)
+	_, err = getPropertyPath(root, []any{"hosts", 2})
+	require.ErrorIs(t, err, ErrPathNotFound)
+	_, err = getPropertyPath(root, []any{"hosts", "name"})
+	require.ErrorIs(t, err, ErrPathNotFound)
+}
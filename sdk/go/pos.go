@@ -0,0 +1,34 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import "unicode/utf8"
+
+// PosFromByteOffset returns the position of the given byte offset within source. Lines and columns are counted
+// from 1. Columns are counted in characters (Unicode code points), which matches the service's visual columns
+// for text without combining characters or wide glyphs. An offset within a multibyte character refers to that
+// character. Offsets are clamped to the bounds of source.
+func PosFromByteOffset(source string, byteOffset int) Pos {
+	if byteOffset < 0 {
+		byteOffset = 0
+	}
+	if byteOffset > len(source) {
+		byteOffset = len(source)
+	}
+
+	line, column := 1, 1
+	for i := 0; i < byteOffset; {
+		r, size := utf8.DecodeRuneInString(source[i:])
+		if i+size > byteOffset {
+			break
+		}
+		if r == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+		i += size
+	}
+
+	return Pos{Line: int32(line), Column: int32(column), Byte: int32(byteOffset)}
+}
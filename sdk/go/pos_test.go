@@ -0,0 +1,35 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PosFromByteOffset(t *testing.T) {
+	source := "values:\n  greeting: héllo 世界\n  next: 1\n"
+
+	tests := []struct {
+		offset   int
+		expected Pos
+	}{
+		{offset: 0, expected: Pos{Line: 1, Column: 1, Byte: 0}},
+		{offset: 7, expected: Pos{Line: 1, Column: 8, Byte: 7}},
+		{offset: 8, expected: Pos{Line: 2, Column: 1, Byte: 8}},
+		// "é" is two bytes, so the "l" after it is at byte 23 but column 15.
+		{offset: 23, expected: Pos{Line: 2, Column: 15, Byte: 23}},
+		// An offset within a multibyte character refers to that character.
+		{offset: 22, expected: Pos{Line: 2, Column: 14, Byte: 22}},
+		{offset: 27, expected: Pos{Line: 2, Column: 19, Byte: 27}},
+		{offset: 28, expected: Pos{Line: 2, Column: 19, Byte: 28}},
+		{offset: 30, expected: Pos{Line: 2, Column: 20, Byte: 30}},
+		{offset: 34, expected: Pos{Line: 3, Column: 1, Byte: 34}},
+		{offset: -1, expected: Pos{Line: 1, Column: 1, Byte: 0}},
+		{offset: 1000, expected: Pos{Line: 4, Column: 1, Byte: int32(len(source))}},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, PosFromByteOffset(source, tt.offset), tt.offset)
+	}
+}
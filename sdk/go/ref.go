@@ -0,0 +1,54 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// identifierKey matches keys that can be written in an interpolation without brackets.
+var identifierKey = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// indexSegment matches path segments that refer to array elements.
+var indexSegment = regexp.MustCompile(`^[0-9]+$`)
+
+// Ref builds an interpolation that references the property at the given path, e.g. Ref("foo", "weird.key", "0")
+// returns `${foo["weird.key"][0]}`. Keys that are not plain identifiers are quoted, and segments other than the
+// first that consist only of decimal digits are treated as array indices.
+func Ref(segments ...string) (string, error) {
+	if len(segments) == 0 {
+		return "", errors.New("a reference requires at least one path segment")
+	}
+
+	var b strings.Builder
+	b.WriteString("${")
+	for i, segment := range segments {
+		switch {
+		case segment == "":
+			return "", errors.New("reference path segments must not be empty")
+		case i > 0 && indexSegment.MatchString(segment):
+			b.WriteString("[" + segment + "]")
+		case identifierKey.MatchString(segment):
+			if i > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(segment)
+		default:
+			b.WriteString("[" + strconv.Quote(segment) + "]")
+		}
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// MustRef is like Ref, but panics if the reference is invalid. It is intended for references built from literals.
+func MustRef(segments ...string) string {
+	ref, err := Ref(segments...)
+	if err != nil {
+		panic(err)
+	}
+	return ref
+}
@@ -0,0 +1,43 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Ref(t *testing.T) {
+	tests := []struct {
+		segments []string
+		expected string
+		err      string
+	}{
+		{segments: []string{"foo"}, expected: "${foo}"},
+		{segments: []string{"foo", "bar_baz"}, expected: "${foo.bar_baz}"},
+		{segments: []string{"foo", "0", "name"}, expected: "${foo[0].name}"},
+		{segments: []string{"foo", "weird.key", "0"}, expected: `${foo["weird.key"][0]}`},
+		{segments: []string{"aws:region"}, expected: `${["aws:region"]}`},
+		{segments: []string{"0"}, expected: `${["0"]}`},
+		{segments: []string{"foo", "a/b"}, expected: `${foo["a/b"]}`},
+		{segments: []string{"foo", `say "hi"`}, expected: `${foo["say \"hi\""]}`},
+		{segments: nil, err: "a reference requires at least one path segment"},
+		{segments: []string{""}, err: "reference path segments must not be empty"},
+		{segments: []string{"foo", "", "bar"}, err: "reference path segments must not be empty"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expected+tt.err, func(t *testing.T) {
+			ref, err := Ref(tt.segments...)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				require.Panics(t, func() { MustRef(tt.segments...) })
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.expected, ref)
+			require.Equal(t, tt.expected, MustRef(tt.segments...))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AsReference reports whether the given definition value is a reference, i.e. an object whose only property is a
+// `$ref` string, and returns it if so. References may be written into definitions with NewReference; they are read
+// back as plain objects, which AsReference recognizes.
+func AsReference(value any) (*Reference, bool) {
+	switch value := value.(type) {
+	case Reference:
+		return &value, true
+	case *Reference:
+		return value, value != nil
+	case map[string]any:
+		if len(value) != 1 {
+			return nil, false
+		}
+		ref, ok := value["$ref"].(string)
+		if !ok {
+			return nil, false
+		}
+		return NewReference(ref), true
+	default:
+		return nil, false
+	}
+}
+
+// ResolveReferences returns a copy of the given definition in which every reference to a location within the
+// definition itself, e.g. `{"$ref": "#/values/aws/region"}`, is replaced by the value at that location.
+// References to other documents are not resolvable by the service yet and are left as they are, so definitions that
+// use them round-trip unchanged. An error is returned if a local reference does not refer to an existing value or
+// refers to itself, directly or indirectly.
+func ResolveReferences(ctx context.Context, def *EnvironmentDefinition) (*EnvironmentDefinition, error) {
+	normalized, err := normalizeDefinition(def)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := normalized.(map[string]any)
+	if !ok {
+		return def, nil
+	}
+
+	resolved, err := resolveReferences(ctx, root, root, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, err := json.Marshal(resolved)
+	if err != nil {
+		return nil, err
+	}
+	var result EnvironmentDefinition
+	if err := json.Unmarshal(bs, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// resolveReferences resolves the local references within value. resolving holds the references currently being
+// resolved, in order to detect cycles.
+func resolveReferences(ctx context.Context, root map[string]any, value any, resolving []string) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if ref, ok := AsReference(value); ok {
+		if !strings.HasPrefix(ref.Ref, "#") {
+			return value, nil
+		}
+		for _, r := range resolving {
+			if r == ref.Ref {
+				return nil, fmt.Errorf("reference cycle: %v", strings.Join(append(resolving, ref.Ref), " -> "))
+			}
+		}
+
+		target, ok := GetByJSONPointer(root, ref.Ref[1:])
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrPathNotFound, ref.Ref)
+		}
+		return resolveReferences(ctx, root, target, append(resolving, ref.Ref))
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		resolved := make(map[string]any, len(value))
+		for k, v := range value {
+			r, err := resolveReferences(ctx, root, v, resolving)
+			if err != nil {
+				return nil, err
+			}
+			resolved[k] = r
+		}
+		return resolved, nil
+	case []any:
+		resolved := make([]any, len(value))
+		for i, v := range value {
+			r, err := resolveReferences(ctx, root, v, resolving)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = r
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
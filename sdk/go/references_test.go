@@ -0,0 +1,40 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResolveReferences(t *testing.T) {
+	def := &EnvironmentDefinition{Values: &EnvironmentDefinitionValues{
+		AdditionalProperties: map[string]any{
+			"aws":    map[string]any{"region": "us-west-2"},
+			"region": NewReference("#/values/aws/region"),
+			"alias":  map[string]any{"$ref": "#/values/region"},
+			"remote": NewReference("https://example.com/schema.json"),
+		},
+	}}
+
+	resolved, err := ResolveReferences(context.Background(), def)
+	require.Nil(t, err)
+	require.Equal(t, "us-west-2", resolved.Values.AdditionalProperties["region"])
+	require.Equal(t, "us-west-2", resolved.Values.AdditionalProperties["alias"])
+
+	ref, ok := AsReference(resolved.Values.AdditionalProperties["remote"])
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/schema.json", ref.Ref)
+
+	def.Values.AdditionalProperties["missing"] = NewReference("#/values/nope")
+	_, err = ResolveReferences(context.Background(), def)
+	require.ErrorIs(t, err, ErrPathNotFound)
+
+	delete(def.Values.AdditionalProperties, "missing")
+	def.Values.AdditionalProperties["a"] = NewReference("#/values/b")
+	def.Values.AdditionalProperties["b"] = NewReference("#/values/a")
+	_, err = ResolveReferences(context.Background(), def)
+	require.ErrorContains(t, err, "reference cycle")
+}
@@ -0,0 +1,150 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryBaseDelay is the delay before the first retry of a request. Each subsequent retry waits twice as long,
+// up to retryMaxDelay.
+var retryBaseDelay = 500 * time.Millisecond
+
+const retryMaxDelay = 8 * time.Second
+
+// NewConfigurationFromEnvironment is like NewConfiguration, but returns an error if PULUMI_ESC_HTTP_TIMEOUT or
+// PULUMI_ESC_MAX_RETRIES is invalid. NewConfiguration instead ignores an invalid value and keeps the default.
+func NewConfigurationFromEnvironment() (*Configuration, error) {
+	cfg := NewConfiguration()
+	if err := configureFromEnvironment(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// configureFromEnvironment seeds the request timeout and retry policy of cfg from PULUMI_ESC_HTTP_TIMEOUT and
+// PULUMI_ESC_MAX_RETRIES. The timeout may be a duration, e.g. `30s`, or a whole number of seconds. Each valid value
+// is applied even if the other is invalid; the first invalid value is returned as an error.
+func configureFromEnvironment(cfg *Configuration) error {
+	var err error
+	if v := strings.TrimSpace(os.Getenv("PULUMI_ESC_HTTP_TIMEOUT")); v != "" {
+		if timeout, perr := parseTimeout(v); perr != nil {
+			err = fmt.Errorf("invalid PULUMI_ESC_HTTP_TIMEOUT %q: expected a duration such as 30s or a number of seconds", v)
+		} else {
+			cfg.RequestTimeout = timeout
+		}
+	}
+
+	if v := strings.TrimSpace(os.Getenv("PULUMI_ESC_MAX_RETRIES")); v != "" {
+		if retries, perr := strconv.Atoi(v); perr != nil || retries < 0 {
+			if err == nil {
+				err = fmt.Errorf("invalid PULUMI_ESC_MAX_RETRIES %q: expected a non-negative integer", v)
+			}
+		} else {
+			cfg.MaxRetries = retries
+		}
+	}
+
+	return err
+}
+
+func parseTimeout(v string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("negative timeout")
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	timeout, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("negative timeout")
+	}
+	return timeout, nil
+}
+
+// send sends the request using the configured HTTP client, applying the configured request timeout and retrying
+// transient failures up to MaxRetries times with exponential backoff.
+func (c *RawAPIClient) send(request *http.Request) (*http.Response, error) {
+	client := c.cfg.HTTPClient
+	if c.cfg.RequestTimeout > 0 {
+		withTimeout := *client
+		withTimeout.Timeout = c.cfg.RequestTimeout
+		client = &withTimeout
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(request)
+		if attempt >= c.cfg.MaxRetries || !shouldRetry(request, resp, err) {
+			return resp, err
+		}
+
+		if request.Body != nil && request.Body != http.NoBody {
+			if request.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := request.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			request.Body = body
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(retryDelay(attempt))
+		select {
+		case <-timer.C:
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		}
+	}
+}
+
+// shouldRetry reports whether a request that produced the given response or error may be retried.
+// Requests the service rejected before processing them are always retried. Other gateway failures and
+// transport errors are only retried for idempotent methods.
+func shouldRetry(request *http.Request, resp *http.Response, err error) bool {
+	if request.Context().Err() != nil {
+		return false
+	}
+
+	idempotent := false
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		idempotent = true
+	}
+
+	if err != nil {
+		return idempotent
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+	if delay <= 0 || delay > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return delay
+}
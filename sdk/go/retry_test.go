@@ -0,0 +1,75 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConfigurationFromEnvironment(t *testing.T) {
+	t.Setenv("PULUMI_ESC_HTTP_TIMEOUT", "45")
+	t.Setenv("PULUMI_ESC_MAX_RETRIES", "3")
+	configuration, err := NewConfigurationFromEnvironment()
+	require.Nil(t, err)
+	require.Equal(t, 45*time.Second, configuration.RequestTimeout)
+	require.Equal(t, 3, configuration.MaxRetries)
+
+	t.Setenv("PULUMI_ESC_HTTP_TIMEOUT", "1m30s")
+	require.Equal(t, 90*time.Second, NewConfiguration().RequestTimeout)
+
+	t.Setenv("PULUMI_ESC_MAX_RETRIES", "-1")
+	configuration = NewConfiguration()
+	require.Equal(t, 0, configuration.MaxRetries)
+	require.Equal(t, 90*time.Second, configuration.RequestTimeout)
+
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	})
+	_, err = apiClient.OpenEnvironment(NewAuthContext("pul-123"), "test-org", "test-env")
+	require.Nil(t, err)
+
+	configuration, err = NewConfigurationFromEnvironment()
+	require.Nil(t, configuration)
+	require.ErrorContains(t, err, "invalid PULUMI_ESC_MAX_RETRIES")
+
+	t.Setenv("PULUMI_ESC_MAX_RETRIES", "")
+	t.Setenv("PULUMI_ESC_HTTP_TIMEOUT", "soon")
+	_, err = NewConfigurationFromEnvironment()
+	require.EqualError(t, err,
+		`invalid PULUMI_ESC_HTTP_TIMEOUT "soon": expected a duration such as 30s or a number of seconds`)
+}
+
+func Test_Retries(t *testing.T) {
+	defer func(delay time.Duration) { retryBaseDelay = delay }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+
+	var requests int
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}, func(cfg *Configuration) {
+		cfg.MaxRetries = 1
+	})
+	auth := NewAuthContext("pul-123")
+
+	_, err := apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.Error(t, err)
+	require.Equal(t, 2, requests)
+
+	requests = 0
+	apiClient.rawClient.cfg.MaxRetries = 2
+	openEnv, err := apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "1", openEnv.Id)
+	require.Equal(t, 3, requests)
+}
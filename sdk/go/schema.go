@@ -0,0 +1,199 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ReadOpenEnvironmentPropertySchema returns the inferred schema of the property at the given path in the environment
+// with the given open session ID. Schemas are free-form JSON Schema values: objects for most properties, but booleans
+// where the schema permits (true) or forbids (false) any value. The raw decoded schema is returned; use SchemaHints
+// to extract its type and secret hints.
+func (c *EscClient) ReadOpenEnvironmentPropertySchema(ctx context.Context, org, envName, openEnvID, propPath string) (any, error) {
+	if err := c.checkEnvironmentRef(org, envName); err != nil {
+		return nil, err
+	}
+
+	segments, err := ParsePropertyPath(propPath)
+	if err != nil {
+		return nil, err
+	}
+
+	env, resp, err := c.EscAPI.ReadOpenEnvironment(ctx, org, envName, openEnvID).Execute()
+	if err != nil {
+		return nil, wrapNotFound(err, resp, org, envName)
+	}
+
+	return propertySchema(env.Schema, segments)
+}
+
+// propertySchema returns the schema of the property at the given path within the given object or array schema.
+func propertySchema(schema any, path []any) (any, error) {
+	for _, segment := range path {
+		obj, ok := schema.(map[string]any)
+		if !ok {
+			if b, ok := schema.(bool); ok {
+				// A boolean schema applies to every nested value.
+				return b, nil
+			}
+			return nil, fmt.Errorf("%w: %v", ErrPathNotFound, segment)
+		}
+
+		var next any
+		var found bool
+		switch segment := segment.(type) {
+		case string:
+			if props, ok := obj["properties"].(map[string]any); ok {
+				next, found = props[segment]
+			}
+			if !found {
+				next, found = obj["additionalProperties"]
+			}
+		case int:
+			if items, ok := obj["prefixItems"].([]any); ok && segment < len(items) {
+				next, found = items[segment], true
+			}
+			if !found {
+				next, found = obj["items"]
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %v", ErrPathNotFound, segment)
+		}
+		schema = next
+	}
+	return schema, nil
+}
+
+// SchemaHints extracts the type and secret hints from a schema returned by ReadOpenEnvironmentPropertySchema.
+// The type is empty if the schema does not constrain it to a single type.
+func SchemaHints(schema any) (typ string, secret bool) {
+	obj, ok := schema.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	typ, _ = obj["type"].(string)
+	secret, _ = obj["secret"].(bool)
+	return typ, secret
+}
+
+// jsonSchemaDialect is the JSON Schema dialect of the schemas returned by the service.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// SchemaToJSONSchema converts a free-form schema, such as Environment.Schema, CheckEnvironment.Schema or a value
+// returned by ReadOpenEnvironmentPropertySchema, into a JSON Schema document. raw may be a decoded JSON value or
+// raw JSON. Boolean schemas are preserved wherever a subschema is expected, a missing schema is treated as `true`,
+// and an object schema without a `$schema` keyword is given one naming the 2020-12 dialect. An error is returned if a
+// subschema is neither a boolean nor an object.
+func SchemaToJSONSchema(raw interface{}) (json.RawMessage, error) {
+	var schema any
+	switch raw := raw.(type) {
+	case nil:
+		schema = true
+	case json.RawMessage:
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return nil, err
+		}
+	case []byte:
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return nil, err
+		}
+	default:
+		// Round-trip through JSON so that typed values are reduced to plain maps, slices and scalars.
+		bs, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(bs, &schema); err != nil {
+			return nil, err
+		}
+	}
+
+	normalized, err := normalizeSchema("#", schema)
+	if err != nil {
+		return nil, err
+	}
+	if obj, ok := normalized.(map[string]any); ok {
+		if _, ok := obj["$schema"]; !ok {
+			obj["$schema"] = jsonSchemaDialect
+		}
+	}
+	return json.Marshal(normalized)
+}
+
+// Keywords whose values are subschemas, maps of subschemas and arrays of subschemas, respectively.
+var (
+	schemaKeywords = []string{
+		"additionalItems", "additionalProperties", "contains", "else", "if", "items", "not", "propertyNames",
+		"then", "unevaluatedItems", "unevaluatedProperties",
+	}
+	schemaMapKeywords   = []string{"$defs", "definitions", "dependentSchemas", "patternProperties", "properties"}
+	schemaArrayKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+)
+
+// normalizeSchema checks that the schema at the given JSON pointer is a boolean or an object, and normalizes its
+// subschemas in turn.
+func normalizeSchema(pointer string, schema any) (any, error) {
+	switch schema := schema.(type) {
+	case nil:
+		return true, nil
+	case bool:
+		return schema, nil
+	case map[string]any:
+		for _, k := range schemaKeywords {
+			if sub, ok := schema[k]; ok {
+				normalized, err := normalizeSchema(pointer+"/"+k, sub)
+				if err != nil {
+					return nil, err
+				}
+				schema[k] = normalized
+			}
+		}
+		for _, k := range schemaMapKeywords {
+			sub, ok := schema[k]
+			if !ok {
+				continue
+			}
+			subs, ok := sub.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%v/%v: expected an object of schemas, not %T", pointer, k, sub)
+			}
+			names := make([]string, 0, len(subs))
+			for name := range subs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				normalized, err := normalizeSchema(pointer+"/"+k+"/"+escapeJSONPointerToken(name), subs[name])
+				if err != nil {
+					return nil, err
+				}
+				subs[name] = normalized
+			}
+		}
+		for _, k := range schemaArrayKeywords {
+			sub, ok := schema[k]
+			if !ok {
+				continue
+			}
+			subs, ok := sub.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%v/%v: expected an array of schemas, not %T", pointer, k, sub)
+			}
+			for i, s := range subs {
+				normalized, err := normalizeSchema(fmt.Sprintf("%v/%v/%d", pointer, k, i), s)
+				if err != nil {
+					return nil, err
+				}
+				subs[i] = normalized
+			}
+		}
+		return schema, nil
+	default:
+		return nil, fmt.Errorf("%v: expected a schema object or boolean, not %T", pointer, schema)
+	}
+}
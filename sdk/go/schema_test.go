@@ -0,0 +1,70 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SchemaToJSONSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      any
+		expected string
+	}{
+		{name: "true", raw: true, expected: `true`},
+		{name: "false", raw: false, expected: `false`},
+		{name: "nil", raw: nil, expected: `true`},
+		{
+			name: "nested",
+			raw: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"password": map[string]any{"type": "string", "secret": true},
+					"anything": true,
+					"nothing":  false,
+					"hosts": map[string]any{
+						"type":        "array",
+						"prefixItems": []any{map[string]any{"const": "a"}, true},
+						"items":       false,
+					},
+				},
+				"required":             []any{"password"},
+				"additionalProperties": false,
+			},
+			expected: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"password": {"type": "string", "secret": true},
+					"anything": true,
+					"nothing": false,
+					"hosts": {"type": "array", "prefixItems": [{"const": "a"}, true], "items": false}
+				},
+				"required": ["password"],
+				"additionalProperties": false
+			}`,
+		},
+		{
+			name:     "raw JSON",
+			raw:      json.RawMessage(`{"$schema": "custom", "properties": {"a": null}}`),
+			expected: `{"$schema": "custom", "properties": {"a": true}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := SchemaToJSONSchema(tt.raw)
+			require.Nil(t, err)
+			require.JSONEq(t, tt.expected, string(actual))
+		})
+	}
+
+	_, err := SchemaToJSONSchema(map[string]any{"properties": map[string]any{"a/b": "string"}})
+	require.ErrorContains(t, err, "#/properties/a~1b: expected a schema object or boolean")
+
+	_, err = SchemaToJSONSchema(map[string]any{"anyOf": map[string]any{}})
+	require.ErrorContains(t, err, "#/anyOf: expected an array of schemas")
+}
@@ -0,0 +1,85 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"errors"
+)
+
+// contextEnvironmentScope holds the EnvironmentScope used by the scoped client methods.
+var contextEnvironmentScope = contextKey("environmentScope")
+
+// ErrNoEnvironmentScope is returned by the scoped client methods when the context carries no environment scope.
+var ErrNoEnvironmentScope = errors.New("context has no environment scope; use WithEnvironmentScope")
+
+// EnvironmentScope identifies the environment that scoped client methods operate on.
+type EnvironmentScope struct {
+	Org     string
+	EnvName string
+}
+
+// WithEnvironmentScope returns a copy of ctx that carries the given organization and environment name.
+// The scoped client methods, such as OpenAndReadScoped, read the environment to operate on from it.
+func WithEnvironmentScope(ctx context.Context, org, envName string) context.Context {
+	return context.WithValue(ctx, contextEnvironmentScope, EnvironmentScope{Org: org, EnvName: envName})
+}
+
+// EnvironmentScopeFromContext returns the environment scope carried by ctx, if any.
+func EnvironmentScopeFromContext(ctx context.Context) (EnvironmentScope, bool) {
+	scope, ok := ctx.Value(contextEnvironmentScope).(EnvironmentScope)
+	return scope, ok
+}
+
+func requireEnvironmentScope(ctx context.Context) (EnvironmentScope, error) {
+	scope, ok := EnvironmentScopeFromContext(ctx)
+	if !ok {
+		return EnvironmentScope{}, ErrNoEnvironmentScope
+	}
+	return scope, nil
+}
+
+// GetEnvironmentScoped is like GetEnvironment, but reads the environment from the context's scope.
+func (c *EscClient) GetEnvironmentScoped(ctx context.Context) (*EnvironmentDefinition, string, error) {
+	scope, err := requireEnvironmentScope(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return c.GetEnvironment(ctx, scope.Org, scope.EnvName)
+}
+
+// OpenEnvironmentScoped is like OpenEnvironment, but reads the environment from the context's scope.
+func (c *EscClient) OpenEnvironmentScoped(ctx context.Context) (*OpenEnvironment, error) {
+	scope, err := requireEnvironmentScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.OpenEnvironment(ctx, scope.Org, scope.EnvName)
+}
+
+// OpenAndReadScoped is like OpenAndReadEnvironment, but reads the environment from the context's scope.
+func (c *EscClient) OpenAndReadScoped(ctx context.Context) (*Environment, map[string]any, error) {
+	scope, err := requireEnvironmentScope(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.OpenAndReadEnvironment(ctx, scope.Org, scope.EnvName)
+}
+
+// ReadEnvironmentPropertyScoped is like ReadEnvironmentProperty, but reads the environment from the context's scope.
+func (c *EscClient) ReadEnvironmentPropertyScoped(ctx context.Context, openEnvID, propPath string) (*Value, any, error) {
+	scope, err := requireEnvironmentScope(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.ReadEnvironmentProperty(ctx, scope.Org, scope.EnvName, openEnvID, propPath)
+}
+
+// UpdateEnvironmentYamlScoped is like UpdateEnvironmentYaml, but reads the environment from the context's scope.
+func (c *EscClient) UpdateEnvironmentYamlScoped(ctx context.Context, yaml string) (*EnvironmentDiagnostics, error) {
+	scope, err := requireEnvironmentScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateEnvironmentYaml(ctx, scope.Org, scope.EnvName, yaml)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvironmentScopeFromContext(t *testing.T) {
+	_, ok := EnvironmentScopeFromContext(context.Background())
+	require.False(t, ok)
+
+	ctx := WithEnvironmentScope(context.Background(), "test-org", "test-env")
+	scope, ok := EnvironmentScopeFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, EnvironmentScope{Org: "test-org", EnvName: "test-env"}, scope)
+
+	ctx = WithEnvironmentScope(ctx, "other-org", "other-env")
+	scope, _ = EnvironmentScopeFromContext(ctx)
+	require.Equal(t, EnvironmentScope{Org: "other-org", EnvName: "other-env"}, scope)
+}
+
+func Test_ScopedMethods(t *testing.T) {
+	var requests []string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch {
+		case r.Method == http.MethodGet && !strings.Contains(r.URL.Path, "/open"):
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = w.Write([]byte("values:\n  greeting: hello\n"))
+		case r.Method == http.MethodPost:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id": "1"}`))
+		case r.URL.Query().Get("property") != "":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"value": "hello", "trace": ` + testTrace + `}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(testOpenEnvironment))
+		}
+	})
+	ctx := WithEnvironmentScope(NewAuthContext("pul-123"), "test-org", "test-env")
+
+	def, _, err := apiClient.GetEnvironmentScoped(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "hello", def.GetValues().AdditionalProperties["greeting"])
+
+	open, err := apiClient.OpenEnvironmentScoped(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "1", open.Id)
+
+	_, values, err := apiClient.OpenAndReadScoped(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "hunter2", values["password"])
+
+	_, value, err := apiClient.ReadEnvironmentPropertyScoped(ctx, "1", "greeting")
+	require.Nil(t, err)
+	require.Equal(t, "hello", value)
+
+	_, err = apiClient.UpdateEnvironmentYamlScoped(ctx, "values:\n  greeting: hi\n")
+	require.Nil(t, err)
+
+	require.Equal(t, []string{
+		"GET /environments/test-org/test-env",
+		"POST /environments/test-org/test-env/open",
+		"POST /environments/test-org/test-env/open",
+		"GET /environments/test-org/test-env/open/1",
+		"GET /environments/test-org/test-env/open//1",
+		"PATCH /environments/test-org/test-env",
+	}, requests)
+}
+
+func Test_ScopedMethodsWithoutScope(t *testing.T) {
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+	})
+	auth := NewAuthContext("pul-123")
+
+	_, _, err := apiClient.GetEnvironmentScoped(auth)
+	require.ErrorIs(t, err, ErrNoEnvironmentScope)
+	_, err = apiClient.OpenEnvironmentScoped(auth)
+	require.ErrorIs(t, err, ErrNoEnvironmentScope)
+	_, _, err = apiClient.OpenAndReadScoped(auth)
+	require.ErrorIs(t, err, ErrNoEnvironmentScope)
+	_, _, err = apiClient.ReadEnvironmentPropertyScoped(auth, "1", "greeting")
+	require.ErrorIs(t, err, ErrNoEnvironmentScope)
+	_, err = apiClient.UpdateEnvironmentYamlScoped(auth, "values: {}")
+	require.ErrorIs(t, err, ErrNoEnvironmentScope)
+}
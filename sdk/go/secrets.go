@@ -0,0 +1,94 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// clientSecretPrefix tags the `fn::secret` values that were encrypted with the configuration's SecretEncryptFunc.
+// The ciphertext follows it, base64-encoded. Only tagged values are decrypted with SecretDecryptFunc, so that
+// secrets written without client-side encryption, e.g. with the console or the CLI, are left as they are.
+const clientSecretPrefix = "esc-sdk:enc:"
+
+// transformSecrets returns a copy of the given definition value in which the plaintext of every `fn::secret` value
+// has been replaced with the result of fn. Secrets that are already encrypted by the service, i.e. whose value is
+// not a string, are left as they are.
+func transformSecrets(value any, fn func(string) (string, error)) (any, error) {
+	switch value := value.(type) {
+	case map[string]any:
+		if plaintext, ok := value["fn::secret"].(string); ok && len(value) == 1 {
+			transformed, err := fn(plaintext)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"fn::secret": transformed}, nil
+		}
+
+		result := make(map[string]any, len(value))
+		for k, v := range value {
+			transformed, err := transformSecrets(v, fn)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = transformed
+		}
+		return result, nil
+	case []any:
+		result := make([]any, len(value))
+		for i, v := range value {
+			transformed, err := transformSecrets(v, fn)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = transformed
+		}
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+// encryptSecrets applies the configured SecretEncryptFunc to the `fn::secret` values within the given value and tags
+// the results with clientSecretPrefix. Values that are already tagged are left as they are.
+func (c *EscClient) encryptSecrets(value any) (any, error) {
+	encrypt := c.rawClient.cfg.SecretEncryptFunc
+	if encrypt == nil {
+		return value, nil
+	}
+	return transformSecrets(value, func(plaintext string) (string, error) {
+		if strings.HasPrefix(plaintext, clientSecretPrefix) {
+			return plaintext, nil
+		}
+		ciphertext, err := encrypt([]byte(plaintext))
+		if err != nil {
+			return "", fmt.Errorf("encrypting secret: %w", err)
+		}
+		return clientSecretPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+	})
+}
+
+// decryptSecrets applies the configured SecretDecryptFunc to the `fn::secret` values within the given value that are
+// tagged with clientSecretPrefix. Other values are left as they are.
+func (c *EscClient) decryptSecrets(value any) (any, error) {
+	decrypt := c.rawClient.cfg.SecretDecryptFunc
+	if decrypt == nil {
+		return value, nil
+	}
+	return transformSecrets(value, func(tagged string) (string, error) {
+		if !strings.HasPrefix(tagged, clientSecretPrefix) {
+			return tagged, nil
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(tagged, clientSecretPrefix))
+		if err != nil {
+			return "", fmt.Errorf("decrypting secret: not base64-encoded: %w", err)
+		}
+		plaintext, err := decrypt(ciphertext)
+		if err != nil {
+			return "", fmt.Errorf("decrypting secret: %w", err)
+		}
+		return string(plaintext), nil
+	})
+}
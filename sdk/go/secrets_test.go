@@ -0,0 +1,78 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func reverseBytes(b []byte) ([]byte, error) {
+	reversed := make([]byte, len(b))
+	for i, c := range b {
+		reversed[len(b)-1-i] = c
+	}
+	return reversed, nil
+}
+
+func Test_TransformSecrets(t *testing.T) {
+	value := map[string]any{
+		"password": map[string]any{"fn::secret": "hunter2"},
+		"stored":   map[string]any{"fn::secret": map[string]any{"ciphertext": "ZXNj"}},
+		"list":     []any{map[string]any{"fn::secret": "a"}, "b"},
+		"tagged":   map[string]any{"fn::secret": "esc-sdk:enc:dGVyY2Vz"},
+	}
+
+	configuration := NewConfiguration()
+	configuration.SecretEncryptFunc = reverseBytes
+	configuration.SecretDecryptFunc = reverseBytes
+	apiClient := NewClient(configuration)
+
+	encrypted, err := apiClient.encryptSecrets(value)
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{
+		"password": map[string]any{"fn::secret": "esc-sdk:enc:" + base64.StdEncoding.EncodeToString([]byte("2retnuh"))},
+		"stored":   map[string]any{"fn::secret": map[string]any{"ciphertext": "ZXNj"}},
+		"list":     []any{map[string]any{"fn::secret": "esc-sdk:enc:" + base64.StdEncoding.EncodeToString([]byte("a"))}, "b"},
+		"tagged":   map[string]any{"fn::secret": "esc-sdk:enc:dGVyY2Vz"},
+	}, encrypted)
+	require.Equal(t, "hunter2", value["password"].(map[string]any)["fn::secret"])
+
+	decrypted, err := apiClient.decryptSecrets(encrypted)
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{
+		"password": map[string]any{"fn::secret": "hunter2"},
+		"stored":   map[string]any{"fn::secret": map[string]any{"ciphertext": "ZXNj"}},
+		"list":     []any{map[string]any{"fn::secret": "a"}, "b"},
+		"tagged":   map[string]any{"fn::secret": "secret"},
+	}, decrypted)
+
+	// Secrets that were not encrypted on the client are not decrypted.
+	plain := map[string]any{"fn::secret": "not base64!"}
+	decrypted, err = apiClient.decryptSecrets(plain)
+	require.Nil(t, err)
+	require.Equal(t, plain, decrypted)
+
+	_, err = apiClient.decryptSecrets(map[string]any{"fn::secret": "esc-sdk:enc:not base64!"})
+	require.ErrorContains(t, err, "not base64-encoded")
+}
+
+func Test_DecryptEnvironmentWithSecretDecryptFunc(t *testing.T) {
+	ciphertext := "esc-sdk:enc:" + base64.StdEncoding.EncodeToString([]byte("2retnuh"))
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-yaml")
+		_, _ = w.Write([]byte("# comment\nvalues:\n  password:\n    fn::secret: " + ciphertext + "\n" +
+			"  token:\n    fn::secret: hunter2\n"))
+	}, func(cfg *Configuration) {
+		cfg.SecretDecryptFunc = reverseBytes
+	})
+
+	def, yaml, err := apiClient.DecryptEnvironment(NewAuthContext("pul-123"), "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "values:\n  password:\n    fn::secret: hunter2\n  token:\n    fn::secret: hunter2\n", yaml)
+	require.Equal(t, map[string]any{"fn::secret": "hunter2"}, def.Values.AdditionalProperties["password"])
+	require.Equal(t, map[string]any{"fn::secret": "hunter2"}, def.Values.AdditionalProperties["token"])
+}
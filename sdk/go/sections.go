@@ -0,0 +1,80 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// OpenAndReadEnvironmentSections opens and reads the environment with the given name in the given organization and
+// returns its well-known top-level sections: the values under `pulumiConfig`, the values under `environmentVariables`
+// and the contents of the values under `files`. Environment variables and files must resolve to strings; any other
+// value is an error. Sections that are not defined by the environment are returned as empty maps.
+func (c *EscClient) OpenAndReadEnvironmentSections(
+	ctx context.Context,
+	org, envName string,
+) (pulumiConfig map[string]any, envVars map[string]string, files map[string][]byte, err error) {
+	_, values, err := c.OpenAndReadEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return splitEnvironmentSections(values)
+}
+
+// ReadEnvironmentVariables opens and reads the environment with the given name in the given organization and returns
+// the values under its `environmentVariables` section, e.g. to export them to a child process. Every environment
+// variable must resolve to a string; any other value is an error. If the environment defines no environment
+// variables, an empty map is returned. Secrets are returned in plaintext.
+func (c *EscClient) ReadEnvironmentVariables(ctx context.Context, org, envName string) (map[string]string, error) {
+	_, envVars, _, err := c.OpenAndReadEnvironmentSections(ctx, org, envName)
+	return envVars, err
+}
+
+// splitEnvironmentSections splits resolved environment values into their well-known top-level sections.
+func splitEnvironmentSections(values map[string]any) (map[string]any, map[string]string, map[string][]byte, error) {
+	pulumiConfig, err := environmentSection(values, "pulumiConfig")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	vars, err := environmentSection(values, "environmentVariables")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	envVars := make(map[string]string, len(vars))
+	for k, v := range vars {
+		s, ok := v.(string)
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("environment variable %q must be a string, not %T", k, v)
+		}
+		envVars[k] = s
+	}
+
+	fileValues, err := environmentSection(values, "files")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	files := make(map[string][]byte, len(fileValues))
+	for k, v := range fileValues {
+		s, ok := v.(string)
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("file %q must be a string, not %T", k, v)
+		}
+		files[k] = []byte(s)
+	}
+
+	return pulumiConfig, envVars, files, nil
+}
+
+func environmentSection(values map[string]any, name string) (map[string]any, error) {
+	v, ok := values[name]
+	if !ok || v == nil {
+		return map[string]any{}, nil
+	}
+	section, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%v must be an object, not %T", name, v)
+	}
+	return section, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"net/http"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitEnvironmentSections(t *testing.T) {
+	pulumiConfig, envVars, files, err := splitEnvironmentSections(map[string]any{
+		"pulumiConfig":         map[string]any{"aws:region": "us-west-2", "count": 3.0},
+		"environmentVariables": map[string]any{"FOO": "bar"},
+		"files":                map[string]any{"KUBECONFIG": "apiVersion: v1"},
+		"other":                "ignored",
+	})
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{"aws:region": "us-west-2", "count": 3.0}, pulumiConfig)
+	require.Equal(t, map[string]string{"FOO": "bar"}, envVars)
+	require.Equal(t, map[string][]byte{"KUBECONFIG": []byte("apiVersion: v1")}, files)
+
+	pulumiConfig, envVars, files, err = splitEnvironmentSections(map[string]any{})
+	require.Nil(t, err)
+	require.Empty(t, pulumiConfig)
+	require.Empty(t, envVars)
+	require.Empty(t, files)
+
+	_, _, _, err = splitEnvironmentSections(map[string]any{"environmentVariables": map[string]any{"PORT": 8080.0}})
+	require.ErrorContains(t, err, `environment variable "PORT" must be a string`)
+
+	_, _, _, err = splitEnvironmentSections(map[string]any{"files": "nope"})
+	require.ErrorContains(t, err, "files must be an object")
+}
+
+func Test_ReadEnvironmentVariables(t *testing.T) {
+	environments := map[string]string{
+		"app": `{"properties": {
+			"environmentVariables": {"value": {
+				"REGION": {"value": "us-west-2", "trace": ` + testTrace + `},
+				"TOKEN": {"value": "hunter2", "secret": true, "trace": ` + testTrace + `}
+			}, "trace": ` + testTrace + `},
+			"pulumiConfig": {"value": {"port": {"value": 8080, "trace": ` + testTrace + `}}, "trace": ` + testTrace + `}
+		}}`,
+		"bad": `{"properties": {
+			"environmentVariables": {"value": {"PORT": {"value": 8080, "trace": ` + testTrace + `}}, "trace": ` + testTrace + `}
+		}}`,
+		"none": `{"properties": {"greeting": {"value": "hello", "trace": ` + testTrace + `}}}`,
+	}
+
+	var requests []string
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			envName := path.Base(path.Dir(r.URL.Path))
+			if _, ok := environments[envName]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code": 404, "message": "not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id": "` + envName + `-session"}`))
+			return
+		}
+		_, _ = w.Write([]byte(environments[path.Base(path.Dir(path.Dir(r.URL.Path)))]))
+	})
+	auth := NewAuthContext("pul-123")
+
+	envVars, err := apiClient.ReadEnvironmentVariables(auth, "test-org", "app")
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"REGION": "us-west-2", "TOKEN": "hunter2"}, envVars)
+	require.Equal(t, []string{
+		"POST /environments/test-org/app/open",
+		"GET /environments/test-org/app/open/app-session",
+	}, requests)
+
+	envVars, err = apiClient.ReadEnvironmentVariables(auth, "test-org", "none")
+	require.Nil(t, err)
+	require.Empty(t, envVars)
+
+	_, err = apiClient.ReadEnvironmentVariables(auth, "test-org", "bad")
+	require.ErrorContains(t, err, `environment variable "PORT" must be a string`)
+
+	_, err = apiClient.ReadEnvironmentVariables(auth, "test-org", "missing")
+	require.ErrorIs(t, err, ErrEnvironmentNotFound)
+}
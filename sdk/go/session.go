@@ -0,0 +1,84 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrSessionClosed is returned when reading from an OpenSession that has been closed.
+var ErrSessionClosed = errors.New("open session is closed")
+
+// OpenSession is an open environment session. It holds the session ID returned when the environment was opened
+// and reuses it for every read, so that reading many properties does not re-open the environment.
+// An OpenSession is safe for concurrent use.
+type OpenSession struct {
+	client  *EscClient
+	org     string
+	envName string
+	id      string
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// OpenSession opens the environment with the given name in the given organization and returns a session
+// for reading its values.
+func (c *EscClient) OpenSession(ctx context.Context, org, envName string) (*OpenSession, error) {
+	openInfo, err := c.OpenEnvironment(ctx, org, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenSession{client: c, org: org, envName: envName, id: openInfo.Id}, nil
+}
+
+// ID returns the ID of the open session.
+func (s *OpenSession) ID() string {
+	return s.id
+}
+
+// ReadProperty reads the property at the given path and returns it along with its resolved value.
+func (s *OpenSession) ReadProperty(ctx context.Context, propPath string) (*Value, any, error) {
+	if err := s.checkOpen(); err != nil {
+		return nil, nil, err
+	}
+
+	prop, resp, err := s.client.EscAPI.ReadOpenEnvironmentProperty(ctx, s.org, s.envName, s.id).Property(propPath).Execute()
+	if err != nil {
+		return nil, nil, wrapNotFound(err, resp, s.org, s.envName)
+	}
+
+	return prop, resolvePropertyValue(prop), nil
+}
+
+// ReadAll reads the whole environment and returns the config and resolved secret values.
+func (s *OpenSession) ReadAll(ctx context.Context) (*Environment, map[string]any, error) {
+	if err := s.checkOpen(); err != nil {
+		return nil, nil, err
+	}
+
+	return s.client.ReadOpenEnvironment(ctx, s.org, s.envName, s.id)
+}
+
+// Close closes the session. Subsequent reads return ErrSessionClosed. The session itself expires on the
+// service once its open duration has elapsed.
+func (s *OpenSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+func (s *OpenSession) checkOpen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSessionClosed
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OpenSession(t *testing.T) {
+	var opens, reads int
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/environments/test-org/test-env/open":
+			opens++
+			_, _ = w.Write([]byte(`{"id": "session-1"}`))
+		case r.URL.Path == "/environments/test-org/test-env/open//session-1" && r.URL.Query().Get("property") == "greeting":
+			reads++
+			_, _ = w.Write([]byte(`{"value": "hello", "trace": ` + testTrace + `}`))
+		case r.URL.Path == "/environments/test-org/test-env/open//session-1" && r.URL.Query().Get("property") == "pulumiConfig":
+			_, _ = w.Write([]byte(`{"value": {
+				"port": {"value": 8080, "trace": ` + testTrace + `},
+				"hosts": {"value": [{"value": "a", "trace": ` + testTrace + `}], "trace": ` + testTrace + `}
+			}, "trace": ` + testTrace + `}`))
+		case r.URL.Path == "/environments/test-org/test-env/open/session-1":
+			reads++
+			_, _ = w.Write([]byte(testOpenEnvironment))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 404, "message": "not found"}`))
+		}
+	})
+	auth := NewAuthContext("pul-123")
+
+	session, err := apiClient.OpenSession(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "session-1", session.ID())
+
+	for i := 0; i < 2; i++ {
+		_, value, err := session.ReadProperty(auth, "greeting")
+		require.Nil(t, err)
+		require.Equal(t, "hello", value)
+
+		_, values, err := session.ReadAll(auth)
+		require.Nil(t, err)
+		require.Equal(t, "hunter2", values["password"])
+	}
+	require.Equal(t, 1, opens)
+	require.Equal(t, 4, reads)
+
+	prop, value, err := session.ReadProperty(auth, "pulumiConfig")
+	require.Nil(t, err)
+	require.Equal(t, map[string]any{"port": 8080.0, "hosts": []any{"a"}}, value)
+	require.Equal(t, 8080.0, prop.Value.(map[string]Value)["port"].Value)
+
+	_, _, err = session.ReadProperty(auth, "missing")
+	require.ErrorIs(t, err, ErrEnvironmentNotFound)
+
+	require.Nil(t, session.Close())
+	_, _, err = session.ReadProperty(auth, "greeting")
+	require.ErrorIs(t, err, ErrSessionClosed)
+	_, _, err = session.ReadAll(auth)
+	require.ErrorIs(t, err, ErrSessionClosed)
+	require.Equal(t, 1, opens)
+	require.Equal(t, 4, reads)
+
+	_, err = apiClient.OpenSession(auth, "test-org", "missing-env")
+	require.Error(t, err)
+}
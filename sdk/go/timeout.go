@@ -0,0 +1,97 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrOpenTimeout is returned, wrapped in an OpenTimeoutError, when an environment does not finish resolving within
+// the timeout given to OpenAndReadEnvironmentWithTimeout.
+var ErrOpenTimeout = errors.New("timed out resolving environment")
+
+// OpenTimeoutError reports that an environment did not finish resolving in time.
+type OpenTimeoutError struct {
+	Org     string
+	EnvName string
+	// Timeout is the timeout that was exceeded.
+	Timeout time.Duration
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *OpenTimeoutError) Error() string {
+	return fmt.Sprintf("open environment %s/%s: timed out after %v", e.Org, e.EnvName, e.Timeout)
+}
+
+// Is reports whether target is ErrOpenTimeout.
+func (e *OpenTimeoutError) Is(target error) bool {
+	return target == ErrOpenTimeout
+}
+
+func (e *OpenTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnknownValues is returned, wrapped in an UnknownValuesError, when an environment is read in time but some of its
+// values are still unknown.
+var ErrUnknownValues = errors.New("environment has unknown values")
+
+// UnknownValuesError reports the values of an environment that were still unknown when it was read.
+type UnknownValuesError struct {
+	Org     string
+	EnvName string
+	// Paths holds the property paths of the unknown values, sorted.
+	Paths []string
+}
+
+func (e *UnknownValuesError) Error() string {
+	return fmt.Sprintf("environment %s/%s has unknown values: %s", e.Org, e.EnvName, strings.Join(e.Paths, ", "))
+}
+
+// Is reports whether target is ErrUnknownValues.
+func (e *UnknownValuesError) Is(target error) bool {
+	return target == ErrUnknownValues
+}
+
+// OpenAndReadEnvironmentWithTimeout opens and reads the environment with the given name in the given organization
+// as OpenAndReadEnvironment does, giving up once the given timeout has elapsed.
+//
+// The service resolves every dynamic provider while opening an environment and does not accept a timeout for them;
+// the duration accepted when opening an environment only sets the lifetime of the open session. The timeout is
+// therefore enforced by the client as a deadline on ctx covering both the open and the read, so a single slow
+// provider fails the whole call. When the deadline expires, an *OpenTimeoutError is returned that matches both
+// ErrOpenTimeout and context.DeadlineExceeded. If the environment is read in time but some of its values are
+// still unknown, e.g. because a provider did not produce them, an *UnknownValuesError naming their paths is returned
+// along with the environment and its values.
+func (c *EscClient) OpenAndReadEnvironmentWithTimeout(
+	ctx context.Context,
+	org, envName string,
+	timeout time.Duration,
+) (*Environment, map[string]any, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	env, values, err := c.OpenAndReadEnvironment(timeoutCtx, org, envName)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+			return nil, nil, &OpenTimeoutError{Org: org, EnvName: envName, Timeout: timeout, Err: err}
+		}
+		return nil, nil, err
+	}
+
+	var unknown []string
+	walkResolvedProperties(env.GetProperties(), func(path string, _ any, _, isUnknown bool) {
+		if isUnknown {
+			unknown = append(unknown, path)
+		}
+	})
+	if len(unknown) != 0 {
+		return env, values, &UnknownValuesError{Org: org, EnvName: envName, Paths: unknown}
+	}
+	return env, values, nil
+}
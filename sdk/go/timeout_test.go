@@ -0,0 +1,57 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OpenAndReadEnvironmentWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/open") {
+			if strings.Contains(r.URL.Path, "/slow-env/") {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}
+			_, _ = w.Write([]byte(`{"id": "1"}`))
+			return
+		}
+		if strings.Contains(r.URL.Path, "/pending-env/") {
+			_, _ = w.Write([]byte(`{"properties": {
+				"ready": {"value": "yes", "trace": ` + testTrace + `},
+				"creds": {"value": {"token": {"value": null, "unknown": true, "trace": ` + testTrace + `}}, "trace": ` + testTrace + `}
+			}}`))
+			return
+		}
+		_, _ = w.Write([]byte(testOpenEnvironment))
+	})
+	// Cleanups run last-registered first, so the blocked handlers are released before the server is closed.
+	t.Cleanup(func() { close(release) })
+	auth := NewAuthContext("pul-123")
+
+	_, values, err := apiClient.OpenAndReadEnvironmentWithTimeout(auth, "test-org", "test-env", time.Minute)
+	require.Nil(t, err)
+	require.Equal(t, "hunter2", values["password"])
+
+	_, _, err = apiClient.OpenAndReadEnvironmentWithTimeout(auth, "test-org", "slow-env", 50*time.Millisecond)
+	require.ErrorIs(t, err, ErrOpenTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, "open environment test-org/slow-env: timed out after 50ms", err.Error())
+
+	env, _, err := apiClient.OpenAndReadEnvironmentWithTimeout(auth, "test-org", "pending-env", time.Minute)
+	require.NotNil(t, env)
+	require.ErrorIs(t, err, ErrUnknownValues)
+	require.NotErrorIs(t, err, ErrOpenTimeout)
+	require.Equal(t, []string{"creds.token"}, err.(*UnknownValuesError).Paths)
+	require.Equal(t, "environment test-org/pending-env has unknown values: creds.token", err.Error())
+}
@@ -0,0 +1,77 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// contextTokenRefresh marks the context passed to a TokenProvider when the previous token was rejected.
+var contextTokenRefresh = contextKey("tokenRefresh")
+
+// TokenRefreshRequested reports whether a TokenProvider is being called with the given context because the service
+// rejected the previous token, in which case the provider should not return a cached token.
+func TokenRefreshRequested(ctx context.Context) bool {
+	refresh, _ := ctx.Value(contextTokenRefresh).(bool)
+	return refresh
+}
+
+// authorizeRequest sets the Authorization header of the request to a token from the configured TokenProvider, if any.
+// The token is sent with the scheme prefix from the request context's API key if there is one, or "token" otherwise.
+func (c *RawAPIClient) authorizeRequest(request *http.Request) error {
+	if c.cfg.TokenProvider == nil {
+		return nil
+	}
+
+	ctx := request.Context()
+	token, err := c.cfg.TokenProvider(ctx)
+	if err != nil {
+		return fmt.Errorf("obtaining access token: %w", err)
+	}
+
+	prefix := "token"
+	if auth, ok := ctx.Value(ContextAPIKeys).(map[string]APIKey); ok {
+		if apiKey, ok := auth["Authorization"]; ok {
+			prefix = apiKey.Prefix
+		}
+	}
+	if prefix != "" {
+		token = prefix + " " + token
+	}
+	request.Header.Set("Authorization", token)
+	return nil
+}
+
+// sendWithRefresh sends the request and, if the configuration enables RefreshOnUnauthorized and the request is rejected
+// with 401 Unauthorized, retries it exactly once with a token refreshed from the TokenProvider. A request whose body
+// cannot be replayed is not retried.
+func (c *RawAPIClient) sendWithRefresh(request *http.Request) (*http.Response, error) {
+	resp, err := c.send(request)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized ||
+		!c.cfg.RefreshOnUnauthorized || c.cfg.TokenProvider == nil {
+		return resp, err
+	}
+
+	if request.Body != nil && request.Body != http.NoBody {
+		if request.GetBody == nil {
+			return resp, err
+		}
+		body, bodyErr := request.GetBody()
+		if bodyErr != nil {
+			return resp, err
+		}
+		request.Body = body
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	ctx := request.Context()
+	refreshed := request.WithContext(context.WithValue(ctx, contextTokenRefresh, true))
+	if err := c.authorizeRequest(refreshed); err != nil {
+		return nil, err
+	}
+	return c.send(refreshed.WithContext(ctx))
+}
@@ -0,0 +1,85 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TokenProvider(t *testing.T) {
+	var authorization string
+	calls := 0
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}, WithTokenProvider(func(ctx context.Context) (string, error) {
+		calls++
+		return "pul-" + strconv.Itoa(calls), nil
+	}))
+	serverURL := apiClient.rawClient.cfg.Servers[0].URL
+
+	_, err := apiClient.OpenEnvironment(NewAuthContext("pul-static"), "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "token pul-1", authorization)
+
+	_, _, err = apiClient.rawRequest(NewAuthContextWithPrefix("pul-static", "Bearer"), "Test", http.MethodGet, serverURL, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, "Bearer pul-2", authorization)
+
+	_, err = apiClient.OpenEnvironment(context.Background(), "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "token pul-3", authorization)
+
+	apiClient.rawClient.cfg.TokenProvider = func(ctx context.Context) (string, error) {
+		return "", errors.New("sts unavailable")
+	}
+	_, err = apiClient.OpenEnvironment(NewAuthContext("pul-static"), "test-org", "test-env")
+	require.ErrorContains(t, err, "obtaining access token: sts unavailable")
+}
+
+func Test_RefreshOnUnauthorized(t *testing.T) {
+	var requests []string
+	token := "stale"
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "token fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}, WithTokenProvider(func(ctx context.Context) (string, error) {
+		if TokenRefreshRequested(ctx) {
+			return "fresh", nil
+		}
+		return token, nil
+	}))
+	configuration := apiClient.rawClient.cfg
+	auth := NewAuthContext("pul-static")
+
+	_, err := apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.Error(t, err)
+	require.Equal(t, []string{"token stale"}, requests)
+
+	requests = nil
+	configuration.RefreshOnUnauthorized = true
+	openEnv, err := apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.Nil(t, err)
+	require.Equal(t, "1", openEnv.Id)
+	require.Equal(t, []string{"token stale", "token fresh"}, requests)
+
+	requests = nil
+	configuration.TokenProvider = func(ctx context.Context) (string, error) {
+		return "revoked", nil
+	}
+	_, err = apiClient.OpenEnvironment(auth, "test-org", "test-env")
+	require.Error(t, err)
+	require.Equal(t, []string{"token revoked", "token revoked"}, requests)
+}
@@ -0,0 +1,42 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer starts a span around each request made to the ESC API.
+// It is deliberately minimal so that callers can adapt OpenTelemetry, or any other
+// tracing library, without the SDK depending on it.
+type Tracer interface {
+	// StartSpan starts a span named after the given API operation (e.g. "OpenEnvironment").
+	// The returned context is used for the outgoing request.
+	StartSpan(ctx context.Context, operation string) (context.Context, Span)
+}
+
+// Span is a single traced API request started by a Tracer.
+type Span interface {
+	// SetStatusCode records the HTTP status code of the response.
+	SetStatusCode(code int)
+	// RecordError records an error that prevented the request from completing.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
+
+// callAPIWithSpan sends the request inside a span started by the configured tracer.
+func (c *RawAPIClient) callAPIWithSpan(request *http.Request, operation string) (*http.Response, error) {
+	ctx, span := c.cfg.Tracer.StartSpan(request.Context(), operation)
+	defer span.End()
+
+	resp, err := c.doRequest(request.WithContext(ctx))
+	if resp != nil {
+		span.SetStatusCode(resp.StatusCode)
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	return resp, err
+}
@@ -0,0 +1,50 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSpan struct {
+	operation  string
+	statusCode int
+	err        error
+	ended      bool
+}
+
+func (s *testSpan) SetStatusCode(code int) { s.statusCode = code }
+func (s *testSpan) RecordError(err error)  { s.err = err }
+func (s *testSpan) End()                   { s.ended = true }
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) StartSpan(ctx context.Context, operation string) (context.Context, Span) {
+	span := &testSpan{operation: operation}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func Test_Tracer(t *testing.T) {
+	tracer := &testTracer{}
+	apiClient := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}, func(cfg *Configuration) {
+		cfg.Tracer = tracer
+	})
+
+	err := apiClient.DeleteEnvironment(NewAuthContext("token"), "test-org", "test-env")
+	require.Error(t, err)
+
+	require.Len(t, tracer.spans, 1)
+	require.Equal(t, "DeleteEnvironment", tracer.spans[0].operation)
+	require.Equal(t, http.StatusNotFound, tracer.spans[0].statusCode)
+	require.Nil(t, tracer.spans[0].err)
+	require.True(t, tracer.spans[0].ended)
+}
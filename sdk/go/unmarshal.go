@@ -0,0 +1,116 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// SecretString holds a secret string value. It redacts itself when formatted, e.g. with fmt or a logger, and when
+// marshaled as JSON or text, so that secrets do not leak by accident; call Reveal to obtain the value.
+type SecretString struct {
+	value string
+}
+
+// NewSecretString returns a SecretString holding the given value.
+func NewSecretString(value string) SecretString {
+	return SecretString{value: value}
+}
+
+// Reveal returns the secret value.
+func (s SecretString) Reveal() string {
+	return s.value
+}
+
+// String returns "[secret]".
+func (s SecretString) String() string {
+	return maskedSecret
+}
+
+// GoString returns "[secret]".
+func (s SecretString) GoString() string {
+	return maskedSecret
+}
+
+// MarshalText returns "[secret]".
+func (s SecretString) MarshalText() ([]byte, error) {
+	return []byte(maskedSecret), nil
+}
+
+// MarshalJSON returns the JSON string "[secret]".
+func (s SecretString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(maskedSecret)
+}
+
+// UnmarshalJSON decodes a JSON string into the secret value.
+func (s *SecretString) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("decoding SecretString: %w", err)
+	}
+	s.value = value
+	return nil
+}
+
+var secretStringType = reflect.TypeOf(SecretString{})
+
+// UnmarshalInto decodes the environment's resolved values into v, as encoding/json would decode their JSON form,
+// so v is typically a pointer to a struct whose `json` tags name the environment's top-level properties.
+//
+// Struct fields tagged `esc:"secret"` must have type SecretString or *SecretString, which keep the value from being
+// logged by accident; a tagged field of any other type is an error, reported before anything is decoded. For example:
+//
+//	type Config struct {
+//		Region   string       `json:"region"`
+//		Password SecretString `json:"password" esc:"secret"`
+//	}
+//
+// Secret values may still be decoded into untagged fields, so tag every field that is expected to hold a secret.
+func (e *Environment) UnmarshalInto(v any) error {
+	if err := checkSecretFields(reflect.TypeOf(v), map[reflect.Type]bool{}); err != nil {
+		return err
+	}
+
+	props := e.GetProperties()
+	values := make(map[string]any, len(props))
+	for k, prop := range props {
+		values[k] = prop.Decode()
+	}
+
+	bs, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, v)
+}
+
+// checkSecretFields checks that every struct field reachable from t that is tagged `esc:"secret"` has type
+// SecretString or *SecretString. seen holds the types already checked, so that recursive types terminate.
+func checkSecretFields(t reflect.Type, seen map[reflect.Type]bool) error {
+	if t == nil || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return checkSecretFields(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Tag.Get("esc") == "secret" {
+				if field.Type != secretStringType && field.Type != reflect.PtrTo(secretStringType) {
+					return fmt.Errorf("field %s.%s is tagged esc:\"secret\" but has type %v, not SecretString",
+						t.Name(), field.Name, field.Type)
+				}
+				continue
+			}
+			if err := checkSecretFields(field.Type, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UnmarshalInto(t *testing.T) {
+	secret := true
+	env := &Environment{Properties: &map[string]Value{
+		"region": {Value: "us-west-2"},
+		"db": {Value: map[string]Value{
+			"user":     {Value: "admin"},
+			"password": {Value: "hunter2", Secret: &secret},
+		}},
+		"apiKeys": {Value: []any{&Value{Value: "k1", Secret: &secret}}},
+	}}
+
+	type dbConfig struct {
+		User     string        `json:"user"`
+		Password *SecretString `json:"password" esc:"secret"`
+	}
+	var config struct {
+		Region  string         `json:"region"`
+		DB      dbConfig       `json:"db"`
+		APIKeys []SecretString `json:"apiKeys"`
+	}
+	require.Nil(t, env.UnmarshalInto(&config))
+	require.Equal(t, "us-west-2", config.Region)
+	require.Equal(t, "admin", config.DB.User)
+	require.Equal(t, "hunter2", config.DB.Password.Reveal())
+	require.Equal(t, "k1", config.APIKeys[0].Reveal())
+
+	require.Equal(t, "[secret]", fmt.Sprintf("%v", config.DB.Password))
+	require.Equal(t, "{admin [secret]}", fmt.Sprintf("%v", config.DB))
+	require.Equal(t, "[secret]", fmt.Sprintf("%#v", *config.DB.Password))
+	bs, err := json.Marshal(config.DB)
+	require.Nil(t, err)
+	require.Equal(t, `{"user":"admin","password":"[secret]"}`, string(bs))
+
+	var invalid struct {
+		DB struct {
+			Password string `json:"password" esc:"secret"`
+		} `json:"db"`
+	}
+	err = env.UnmarshalInto(&invalid)
+	require.ErrorContains(t, err, "Password is tagged esc:\"secret\" but has type string")
+	require.Empty(t, invalid.DB.Password)
+}
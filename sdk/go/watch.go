@@ -0,0 +1,121 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// defaultWatchInterval is the polling interval used by WatchEnvironment when the given interval is not positive.
+const defaultWatchInterval = 30 * time.Second
+
+// WatchEnvironment polls the revisions of the environment with the given name in the given organization every interval
+// and emits each newly created revision, oldest first. If interval is not positive, the environment is polled every
+// 30 seconds. Revisions that already exist when the watch starts are not emitted, and each revision number is emitted
+// at most once. If more revisions were created between two polls than fit on one page of the revision history, the
+// history is paged back through until the last emitted revision, so that none are missed.
+//
+// Errors encountered while polling are sent on the error channel and do not stop the watch. Both channels are closed
+// once ctx is cancelled. If the configuration enables ValidateNames and the names are invalid, that error is sent
+// instead and both channels are closed without polling.
+func (c *EscClient) WatchEnvironment(ctx context.Context, org, envName string, interval time.Duration) (<-chan EnvironmentRevision, <-chan error) {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	revisions := make(chan EnvironmentRevision)
+	errs := make(chan error)
+
+	go func() {
+		defer close(revisions)
+		defer close(errs)
+
+		if err := c.checkEnvironmentRef(org, envName); err != nil {
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+			}
+			return
+		}
+
+		var latest int32
+		initialized := false
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			var revs []EnvironmentRevision
+			var err error
+			if initialized {
+				revs, err = c.revisionsAfter(ctx, org, envName, latest)
+			} else {
+				revs, err = c.ListEnvironmentRevisions(ctx, org, envName)
+			}
+
+			if err != nil {
+				select {
+				case errs <- err:
+				case <-ctx.Done():
+					return
+				}
+			} else {
+				sort.Slice(revs, func(i, j int) bool { return revs[i].Number < revs[j].Number })
+				for _, rev := range revs {
+					if rev.Number <= latest {
+						continue
+					}
+					latest = rev.Number
+					if !initialized {
+						continue
+					}
+					select {
+					case revisions <- rev:
+					case <-ctx.Done():
+						return
+					}
+				}
+				initialized = true
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return revisions, errs
+}
+
+// revisionsAfter returns the revisions of the given environment numbered above latest, paging backwards through the
+// revision history until a page reaches the revision after latest or the first revision.
+func (c *EscClient) revisionsAfter(ctx context.Context, org, envName string, latest int32) ([]EnvironmentRevision, error) {
+	revs, err := c.ListEnvironmentRevisions(ctx, org, envName)
+	var newer []EnvironmentRevision
+	for {
+		if err != nil {
+			return nil, err
+		}
+		if len(revs) == 0 {
+			return newer, nil
+		}
+
+		oldest := revs[0].Number
+		for _, rev := range revs {
+			if rev.Number > latest {
+				newer = append(newer, rev)
+			}
+			if rev.Number < oldest {
+				oldest = rev.Number
+			}
+		}
+		if oldest <= latest+1 || oldest <= 1 {
+			return newer, nil
+		}
+
+		revs, _, err = c.EscAPI.ListEnvironmentRevisions(ctx, org, envName).Before(oldest).Execute()
+	}
+}
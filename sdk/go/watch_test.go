@@ -0,0 +1,120 @@
+// Copyright 2024, Pulumi Corporation.  All rights reserved.
+
+package esc_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testRevisionServer serves the revision history of a single environment, newest first, two revisions per page.
+type testRevisionServer struct {
+	mu      sync.Mutex
+	latest  int32
+	failing bool
+	polled  chan struct{}
+}
+
+func newTestRevisionServer(t *testing.T, latest int32) (*testRevisionServer, *EscClient) {
+	s := &testRevisionServer{latest: latest, polled: make(chan struct{}, 1)}
+	return s, newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/environments/test-org/test-env/versions", r.URL.Path)
+
+		s.mu.Lock()
+		newest, failing := s.latest, s.failing
+		s.mu.Unlock()
+		defer func() {
+			select {
+			case s.polled <- struct{}{}:
+			default:
+			}
+		}()
+
+		w.Header().Set("Content-Type", "application/json")
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"code": 500, "message": "unavailable"}`))
+			return
+		}
+		if before := r.URL.Query().Get("before"); before != "" {
+			n, err := strconv.Atoi(before)
+			require.Nil(t, err)
+			newest = int32(n) - 1
+		}
+		revs := []EnvironmentRevision{}
+		for n := newest; n > 0 && len(revs) < 2; n-- {
+			revs = append(revs, EnvironmentRevision{Number: n})
+		}
+		_ = json.NewEncoder(w).Encode(revs)
+	})
+}
+
+func (s *testRevisionServer) update(fn func(s *testRevisionServer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s)
+}
+
+func receiveRevision(t *testing.T, revisions <-chan EnvironmentRevision) int32 {
+	select {
+	case rev := <-revisions:
+		return rev.Number
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for a revision")
+		return 0
+	}
+}
+
+func Test_WatchEnvironment(t *testing.T) {
+	s, apiClient := newTestRevisionServer(t, 3)
+	ctx, cancel := context.WithCancel(NewAuthContext("pul-123"))
+	defer cancel()
+
+	revisions, errs := apiClient.WatchEnvironment(ctx, "test-org", "test-env", 10*time.Millisecond)
+	<-s.polled
+
+	// More revisions than fit on one page are created between polls.
+	s.update(func(s *testRevisionServer) { s.latest = 8 })
+	for n := int32(4); n <= 8; n++ {
+		require.Equal(t, n, receiveRevision(t, revisions))
+	}
+
+	s.update(func(s *testRevisionServer) { s.failing = true })
+	select {
+	case err := <-errs:
+		require.ErrorContains(t, err, "500")
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for an error")
+	}
+
+	// The watch continues after an error.
+	s.update(func(s *testRevisionServer) { s.failing, s.latest = false, 9 })
+	require.Equal(t, int32(9), receiveRevision(t, revisions))
+
+	cancel()
+	for range revisions {
+	}
+	for range errs {
+	}
+}
+
+func Test_WatchEnvironmentDefaultInterval(t *testing.T) {
+	s, apiClient := newTestRevisionServer(t, 1)
+	ctx, cancel := context.WithCancel(NewAuthContext("pul-123"))
+
+	revisions, errs := apiClient.WatchEnvironment(ctx, "test-org", "test-env", 0)
+	<-s.polled
+	cancel()
+
+	_, ok := <-revisions
+	require.False(t, ok)
+	_, ok = <-errs
+	require.False(t, ok)
+}